@@ -0,0 +1,127 @@
+package gerr
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+)
+
+// CodeMapping translates between gRPC codes and HTTP statuses for reasons
+// that declare only one of them. Configure a mapping before passing it to
+// SetCodeMapping; it must not be modified afterwards.
+type CodeMapping struct {
+	toHTTP map[codes.Code]int
+	toGRPC map[int]codes.Code
+}
+
+// NewCodeMapping returns the canonical mapping. gRPC to HTTP follows the
+// grpc-gateway table; HTTP to gRPC is its inverse for error statuses, picking
+// the most specific code where several gRPC codes share a status.
+func NewCodeMapping() *CodeMapping {
+	return &CodeMapping{
+		toHTTP: map[codes.Code]int{
+			codes.OK:                 http.StatusOK,
+			codes.Canceled:           499,
+			codes.Unknown:            http.StatusInternalServerError,
+			codes.InvalidArgument:    http.StatusBadRequest,
+			codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+			codes.NotFound:           http.StatusNotFound,
+			codes.AlreadyExists:      http.StatusConflict,
+			codes.PermissionDenied:   http.StatusForbidden,
+			codes.Unauthenticated:    http.StatusUnauthorized,
+			codes.ResourceExhausted:  http.StatusTooManyRequests,
+			codes.FailedPrecondition: http.StatusBadRequest,
+			codes.Aborted:            http.StatusConflict,
+			codes.OutOfRange:         http.StatusBadRequest,
+			codes.Unimplemented:      http.StatusNotImplemented,
+			codes.Internal:           http.StatusInternalServerError,
+			codes.Unavailable:        http.StatusServiceUnavailable,
+			codes.DataLoss:           http.StatusInternalServerError,
+		},
+		toGRPC: map[int]codes.Code{
+			http.StatusBadRequest:                   codes.InvalidArgument,
+			http.StatusUnauthorized:                 codes.Unauthenticated,
+			http.StatusForbidden:                    codes.PermissionDenied,
+			http.StatusNotFound:                     codes.NotFound,
+			http.StatusMethodNotAllowed:             codes.Unimplemented,
+			http.StatusRequestTimeout:               codes.DeadlineExceeded,
+			http.StatusConflict:                     codes.Aborted,
+			http.StatusPreconditionFailed:           codes.FailedPrecondition,
+			http.StatusRequestedRangeNotSatisfiable: codes.OutOfRange,
+			http.StatusTooManyRequests:              codes.ResourceExhausted,
+			499:                                     codes.Canceled,
+			http.StatusInternalServerError:          codes.Internal,
+			http.StatusNotImplemented:               codes.Unimplemented,
+			http.StatusBadGateway:                   codes.Unavailable,
+			http.StatusServiceUnavailable:           codes.Unavailable,
+			http.StatusGatewayTimeout:               codes.DeadlineExceeded,
+		},
+	}
+}
+
+// MapGRPC overrides the HTTP status used for code.
+func (m *CodeMapping) MapGRPC(code codes.Code, httpStatus int) *CodeMapping {
+	m.toHTTP[code] = httpStatus
+	return m
+}
+
+// MapHTTP overrides the gRPC code used for httpStatus.
+func (m *CodeMapping) MapHTTP(httpStatus int, code codes.Code) *CodeMapping {
+	m.toGRPC[httpStatus] = code
+	return m
+}
+
+// HTTPFromGRPC returns the HTTP status for code; unmapped codes become 500.
+func (m *CodeMapping) HTTPFromGRPC(code codes.Code) int {
+	if status, ok := m.toHTTP[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCFromHTTP returns the gRPC code for httpStatus. Unmapped statuses fall
+// back by class: 4xx to FailedPrecondition, 5xx to Internal and anything
+// else, 2xx included, to Unknown, so an error never translates into OK.
+func (m *CodeMapping) GRPCFromHTTP(httpStatus int) codes.Code {
+	if code, ok := m.toGRPC[httpStatus]; ok {
+		return code
+	}
+	switch {
+	case httpStatus >= 400 && httpStatus < 500:
+		return codes.FailedPrecondition
+	case httpStatus >= 500 && httpStatus < 600:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
+
+var codeMapping atomic.Pointer[CodeMapping]
+
+func init() {
+	SetCodeMapping(NewCodeMapping())
+}
+
+// SetCodeMapping replaces the mapping used by HTTPFromGRPC, GRPCFromHTTP and
+// the reasons of this package.
+func SetCodeMapping(m *CodeMapping) {
+	if m != nil {
+		codeMapping.Store(m)
+	}
+}
+
+// CurrentCodeMapping returns the mapping in use.
+func CurrentCodeMapping() *CodeMapping {
+	return codeMapping.Load()
+}
+
+// HTTPFromGRPC maps a gRPC code to an HTTP status with the current mapping.
+func HTTPFromGRPC(code codes.Code) int {
+	return CurrentCodeMapping().HTTPFromGRPC(code)
+}
+
+// GRPCFromHTTP maps an HTTP status to a gRPC code with the current mapping.
+func GRPCFromHTTP(httpStatus int) codes.Code {
+	return CurrentCodeMapping().GRPCFromHTTP(httpStatus)
+}
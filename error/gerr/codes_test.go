@@ -0,0 +1,100 @@
+package gerr
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCodeMappingIsConsistentAcrossReasons(t *testing.T) {
+	httpOnly := xerr.NewHTTPReason("USER_NOT_FOUND", "user not found", http.StatusNotFound)
+	if got := GetGRPCCode(httpOnly); got != codes.NotFound {
+		t.Fatalf("expected NotFound for HTTP 404, got %v", got)
+	}
+	if got := ErrorToGRPCCode(xerr.New(httpOnly, nil)); got != codes.NotFound {
+		t.Fatalf("expected NotFound over gRPC, got %v", got)
+	}
+
+	reasons := []xerr.Reason{
+		NewGRPCReason("USER_NOT_FOUND", "user not found", codes.NotFound),
+		NewMultiReason("USER_NOT_FOUND", "user not found", 0, codes.NotFound),
+		NewMultiReason("USER_NOT_FOUND", "user not found", http.StatusNotFound, codes.OK),
+		NewProtoReasonWithGRPC("USER_NOT_FOUND", "user not found", codes.NotFound),
+		NewProtoReasonWithHTTP("USER_NOT_FOUND", "user not found", http.StatusNotFound),
+	}
+	for _, r := range reasons {
+		if got := xerr.GetHTTPCode(r); got != http.StatusNotFound {
+			t.Fatalf("%T: expected HTTP 404, got %d", r, got)
+		}
+		if got := GetGRPCCode(r); got != codes.NotFound {
+			t.Fatalf("%T: expected NotFound, got %v", r, got)
+		}
+	}
+}
+
+func TestGRPCFromHTTPFallsBackByClass(t *testing.T) {
+	cases := map[int]codes.Code{
+		http.StatusTooManyRequests: codes.ResourceExhausted,
+		http.StatusTeapot:          codes.FailedPrecondition,
+		http.StatusLoopDetected:    codes.Internal,
+		http.StatusNoContent:       codes.Unknown,
+		http.StatusOK:              codes.Unknown,
+	}
+	for status, want := range cases {
+		if got := GRPCFromHTTP(status); got != want {
+			t.Fatalf("GRPCFromHTTP(%d) = %v, want %v", status, got, want)
+		}
+	}
+	if got := HTTPFromGRPC(codes.Code(99)); got != http.StatusInternalServerError {
+		t.Fatalf("expected 500 for unknown code, got %d", got)
+	}
+}
+
+func TestSetCodeMappingOverrides(t *testing.T) {
+	zero := int32(codes.OK)
+	for _, reason := range []xerr.Reason{
+		&GRPCReason{SimpleReason: xerr.SimpleReason{ErrorCode: "BARE", ErrorMessage: "bare"}},
+		NewMultiReason("MULTI", "multi", 0, codes.OK),
+		&ProtoReason{ErrorCode: "WIRE", GrpcCode: &zero},
+	} {
+		if got := xerr.ErrorToHTTPStatus(xerr.New(reason, nil)); got != http.StatusInternalServerError {
+			t.Fatalf("expected 500 for %T with an OK code, got %d", reason, got)
+		}
+	}
+	if got := HTTPFromGRPC(codes.OK); got != http.StatusOK {
+		t.Fatalf("expected the mapping to keep OK as 200, got %d", got)
+	}
+
+	prev := CurrentCodeMapping()
+	t.Cleanup(func() { SetCodeMapping(prev) })
+
+	SetCodeMapping(NewCodeMapping().
+		MapGRPC(codes.FailedPrecondition, http.StatusPreconditionFailed).
+		MapHTTP(http.StatusConflict, codes.AlreadyExists))
+
+	if got := HTTPFromGRPC(codes.FailedPrecondition); got != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412, got %d", got)
+	}
+	if got := GetGRPCCode(xerr.NewHTTPReason("DUP", "duplicate", http.StatusConflict)); got != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists, got %v", got)
+	}
+}
+
+func TestNonErrorStatusNeverBecomesOK(t *testing.T) {
+	err := xerr.New(xerr.NewHTTPReason("W", "weird", http.StatusAccepted), nil)
+	if st := ErrorToGRPCStatus(err); st.Err() == nil || st.Code() != codes.Unknown {
+		t.Fatalf("expected an Unknown status error, got %v", st)
+	}
+
+	prev := CurrentCodeMapping()
+	t.Cleanup(func() { SetCodeMapping(prev) })
+	SetCodeMapping(NewCodeMapping().MapHTTP(http.StatusConflict, codes.OK))
+	if got := GetGRPCCode(NewGRPCReason("NOOP", "noop", codes.OK)); got != codes.Unknown {
+		t.Fatalf("expected Unknown for an explicit OK reason, got %v", got)
+	}
+	if got := GetGRPCCode(xerr.NewHTTPReason("DUP", "duplicate", http.StatusConflict)); got != codes.Unknown {
+		t.Fatalf("expected Unknown when the mapping yields OK, got %v", got)
+	}
+}
@@ -0,0 +1,114 @@
+package gerr
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// KeyRetryDelay is the metadata key mapped to google.rpc.RetryInfo. Its value
+// is a duration such as "1.5s" or a slog.Duration attribute.
+const KeyRetryDelay = "retry_delay"
+
+var errorDomain atomic.Pointer[string]
+
+// SetErrorDomain sets the domain reported in google.rpc.ErrorInfo details,
+// usually the service name, e.g. "billing.example.com". It is empty by default.
+func SetErrorDomain(domain string) {
+	errorDomain.Store(&domain)
+}
+
+// CurrentErrorDomain returns the domain reported in google.rpc.ErrorInfo details.
+func CurrentErrorDomain() string {
+	if d := errorDomain.Load(); d != nil {
+		return *d
+	}
+	return ""
+}
+
+// ErrorInfo describes err as a google.rpc.ErrorInfo so that clients not aware
+// of ProtoReason still see the reason code and the public metadata.
+func ErrorInfo(err xerr.Error) *errdetails.ErrorInfo {
+	if err == nil || err.Reason() == nil {
+		return nil
+	}
+	info := &errdetails.ErrorInfo{
+		Reason: string(err.Reason().Code()),
+		Domain: CurrentErrorDomain(),
+	}
+	if md := xerr.PublicMetadata(err); len(md) > 0 {
+		info.Metadata = md
+	}
+	return info
+}
+
+// BadRequest converts field violations into a google.rpc.BadRequest. It
+// returns nil when there are none.
+func BadRequest(violations []xerr.FieldViolation) *errdetails.BadRequest {
+	if len(violations) == 0 {
+		return nil
+	}
+	br := &errdetails.BadRequest{}
+	for _, fv := range violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fv.Field,
+			Description: fv.Message,
+			Reason:      fv.Constraint,
+		})
+	}
+	return br
+}
+
+// RetryInfo returns the google.rpc.RetryInfo advertised for err. The delay
+// comes from the KeyRetryDelay metadata; a retryable error without one gets
+// a zero delay, leaving the backoff to the client. Errors that are neither
+// retryable nor carry a delay get nil.
+func RetryInfo(err xerr.Error) *errdetails.RetryInfo {
+	delay, ok := retryDelay(err)
+	if !ok && !xerr.IsRetryable(err) {
+		return nil
+	}
+	return &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}
+}
+
+func retryDelay(err xerr.Error) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+	raw, ok := err.Metadata()[KeyRetryDelay]
+	if !ok {
+		return 0, false
+	}
+	delay, parseErr := time.ParseDuration(raw)
+	if parseErr != nil || delay < 0 {
+		return 0, false
+	}
+	return delay, true
+}
+
+// fromBadRequest converts a google.rpc.BadRequest back into FieldViolation details.
+func fromBadRequest(br *errdetails.BadRequest) []any {
+	out := make([]any, 0, len(br.GetFieldViolations()))
+	for _, v := range br.GetFieldViolations() {
+		out = append(out, xerr.FieldViolation{
+			Field:      v.GetField(),
+			Constraint: v.GetReason(),
+			Message:    v.GetDescription(),
+		})
+	}
+	return out
+}
+
+func metadataFields(md map[string]string) map[string]any {
+	if len(md) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(md))
+	for k, v := range md {
+		out[k] = v
+	}
+	return out
+}
@@ -1,86 +1,207 @@
 package gerr
 
 import (
+	"errors"
+
 	"github.com/nduyhai/xcore/error/xerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
 )
 
+// FromGRPCStatus converts a status into xerr.Error. Metadata, causes and
+// field violations sent by ErrorToGRPCStatus are restored; statuses from other
+// servers are understood through google.rpc.ErrorInfo and BadRequest.
+// Reason codes registered in xerr.DefaultRegistry are restored to their
+// canonical reasons; unknown codes are kept as *ProtoReason.
 func FromGRPCStatus(st *status.Status) xerr.Error {
 	if st == nil {
 		return nil
 	}
+	return fromStatus(st, nil, xerr.WithCallerSkip(2))
+}
 
-	var reason xerr.Reason
+// FromError converts a gRPC status error into xerr.Error, keeping err as the
+// cause so status.Code and status.FromError keep working on the result.
+// Errors that carry no gRPC status are returned unchanged.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var x xerr.Error
+	if errors.As(err, &x) {
+		return err
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return fromStatus(st, err, xerr.WithoutStack())
+}
 
-	// Look for ProtoReason in status details
+func fromStatus(st *status.Status, cause error, opts ...xerr.Option) xerr.Error {
+	var (
+		reason   xerr.Reason
+		info     *errdetails.ErrorInfo
+		metadata map[string]string
+		causes   []*ProtoReason
+		details  []any
+		delay    string
+	)
 	for _, detail := range st.Details() {
-		if protoReason, ok := detail.(*ProtoReason); ok {
-			reason = protoReason
-			break
-		}
-		// Also check for any other xerr.Reason implementation
-		if xeReason, ok := detail.(xerr.Reason); ok {
-			reason = xeReason
-			break
+		switch d := detail.(type) {
+		case *ProtoReason:
+			if reason == nil {
+				reason, metadata, causes = d, d.GetMetadata(), d.GetCauses()
+			}
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.BadRequest:
+			details = append(details, fromBadRequest(d)...)
+		case *errdetails.RetryInfo:
+			// A RetryInfo without delay still marks the error as retryable
+			delay = d.GetRetryDelay().AsDuration().String()
+		case xerr.Reason:
+			// Also accept any other xerr.Reason implementation
+			if reason == nil {
+				reason = d
+			}
 		}
 	}
 
-	// Default to ProtoReason if no custom reason found in details
+	// Fall back to ErrorInfo from non-Go servers, then to the bare status
+	if reason == nil && info != nil && info.GetReason() != "" {
+		reason = NewProtoReasonWithCodes(xerr.ErrorCode(info.GetReason()), st.Message(), nil, st.Code())
+		metadata = info.GetMetadata()
+	}
 	if reason == nil {
-		reasonCode := xerr.ErrorCode(st.Code().String())
-		message := st.Message()
-		reason = NewProtoReasonWithCodes(reasonCode, message, nil, st.Code())
+		reason = NewProtoReasonWithCodes(xerr.ErrorCode(st.Code().String()), st.Message(), nil, st.Code())
 	}
+	reason = canonicalReason(reason)
 
-	return xerr.New(reason, nil)
+	// Rebuild the cause chain innermost first
+	for i := len(causes) - 1; i >= 0; i-- {
+		cause = xerr.New(canonicalReason(causes[i]), cause, xerr.WithoutStack())
+	}
+
+	err := xerr.New(reason, cause, opts...)
+	if fields := metadataFields(metadata); fields != nil {
+		err = xerr.WithFields(err, fields)
+	}
+	if delay != "" {
+		err = xerr.With(err, KeyRetryDelay, delay)
+	}
+	if len(details) > 0 {
+		err = xerr.WithDetails(err, details...)
+	}
+	return err
+}
+
+// canonicalReason returns the reason registered under the code of r, so that
+// clients get back the reason values they declared, or r itself.
+func canonicalReason(r xerr.Reason) xerr.Reason {
+	if registered, ok := xerr.LookupReason(r.Code()); ok {
+		return registered
+	}
+	return r
 }
 
-// ErrorToGRPCStatus converts xerr.Error to GRPC Status
-func ErrorToGRPCStatus(err xerr.Error) *status.Status {
+// toStatusError converts err into a gRPC status error when it is an
+// xerr.Error; other errors, including existing status errors, pass through.
+func toStatusError(err error, opts ...StatusOption) error {
 	if err == nil {
-		return status.New(codes.OK, "")
+		return nil
+	}
+	var x xerr.Error
+	if !errors.As(err, &x) {
+		return err
 	}
+	return ErrorToGRPCStatus(x, opts...).Err()
+}
 
-	// Get GRPC code from reason
-	grpcCode := GetGRPCCode(err.Reason())
+type statusConfig struct {
+	causes bool
+}
 
-	// Create status with basic info
-	st := status.New(grpcCode, err.Error())
+// StatusOption configures how ErrorToGRPCStatus renders an error.
+type StatusOption func(*statusConfig)
 
-	// Convert reason to ProtoReason and add to details
-	if err.Reason() != nil {
-		var protoReason *ProtoReason
+// WithCauses adds the reasons of wrapped errors to ProtoReason.Causes, so
+// FromGRPCStatus can restore the cause chain. It is off by default because
+// the causes describe the internals of the server.
+func WithCauses() StatusOption {
+	return func(c *statusConfig) { c.causes = true }
+}
 
-		// If the reason is already a ProtoReason, use it directly
-		if existingProtoReason, ok := err.Reason().(*ProtoReason); ok {
-			protoReason = existingProtoReason
-		} else {
-			// Convert other reason types to ProtoReason
-			errorCode := err.Reason().Code()
-			message := err.Reason().Message()
+// ErrorToGRPCStatus converts xerr.Error to GRPC Status. Besides the
+// ProtoReason, the details carry google.rpc.ErrorInfo, BadRequest for field
+// violations and RetryInfo when a retry delay is set, so that clients in
+// other languages understand the error too. Wrapped reasons are only sent
+// with WithCauses.
+func ErrorToGRPCStatus(err xerr.Error, opts ...StatusOption) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
 
-			// Check if reason has HTTP/GRPC codes
-			var httpCode *int
-			var gCode = GetGRPCCode(err.Reason())
+	// Get GRPC code from reason
+	grpcCode := GetGRPCCode(err.Reason())
 
-			if httpAware, ok := err.Reason().(xerr.HTTPAware); ok {
-				httpCodeVal := httpAware.HTTPCode()
-				httpCode = &httpCodeVal
-			}
+	// Create status with basic info; only the public message leaves the process
+	st := status.New(grpcCode, xerr.Public(err).Error())
 
-			protoReason = NewProtoReasonWithCodes(errorCode, message, httpCode, gCode)
-		}
+	if err.Reason() == nil {
+		return st
+	}
 
-		// Add ProtoReason to status details
-		if detailedStatus, detailErr := st.WithDetails(protoReason); detailErr == nil {
-			st = detailedStatus
+	protoReason := toProtoReason(err.Reason())
+	if md := xerr.PublicMetadata(err); len(md) > 0 {
+		protoReason.Metadata = md
+	}
+	var cfg statusConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if reasons := xerr.Reasons(err); cfg.causes && len(reasons) > 1 {
+		for _, r := range reasons[1:] {
+			protoReason.Causes = append(protoReason.Causes, toProtoReason(r))
 		}
 	}
 
+	details := []protoadapt.MessageV1{protoReason, ErrorInfo(err)}
+	if br := BadRequest(xerr.FieldViolations(err)); br != nil {
+		details = append(details, br)
+	}
+	if ri := RetryInfo(err); ri != nil {
+		details = append(details, ri)
+	}
+
+	// Add details; a status without them is still usable
+	if detailedStatus, detailErr := st.WithDetails(details...); detailErr == nil {
+		st = detailedStatus
+	}
 	return st
 }
 
+// toProtoReason converts any reason into a ProtoReason without metadata or causes.
+func toProtoReason(reason xerr.Reason) *ProtoReason {
+	// If the reason is already a ProtoReason, copy it so the original is not modified
+	if existing, ok := reason.(*ProtoReason); ok {
+		clone := proto.Clone(existing).(*ProtoReason)
+		clone.Metadata, clone.Causes = nil, nil
+		return clone
+	}
+
+	// Check if reason has HTTP/GRPC codes
+	var httpCode *int
+	if httpAware, ok := reason.(xerr.HTTPAware); ok {
+		httpCodeVal := httpAware.HTTPCode()
+		httpCode = &httpCodeVal
+	}
+	return NewProtoReasonWithCodes(reason.Code(), reason.Message(), httpCode, GetGRPCCode(reason))
+}
+
 func ErrorToGRPCCode(err xerr.Error) codes.Code {
 	if err == nil {
 		return codes.OK
@@ -14,7 +14,7 @@ type GRPCReason struct {
 	GrpcCode codes.Code `json:"grpc_code"`
 }
 
-func NewGRPCReason(code xerr.ErrorCode, message string, grpcCode codes.Code) xerr.Reason {
+func NewGRPCReason(code xerr.ErrorCode, message string, grpcCode codes.Code) *GRPCReason {
 	return &GRPCReason{
 		SimpleReason: xerr.SimpleReason{
 			ErrorCode:    code,
@@ -27,3 +27,8 @@ func NewGRPCReason(code xerr.ErrorCode, message string, grpcCode codes.Code) xer
 func (r *GRPCReason) GRPCCode() codes.Code {
 	return r.GrpcCode
 }
+
+// HTTPCode derives the HTTP status from the gRPC code through the code mapping
+func (r *GRPCReason) HTTPCode() int {
+	return reasonHTTPFromGRPC(r.GrpcCode)
+}
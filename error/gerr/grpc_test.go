@@ -0,0 +1,157 @@
+package gerr
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	reasonOrderFailed = NewMultiReason("ORDER_FAILED", "order failed", http.StatusConflict, codes.Aborted)
+	reasonStockLow    = NewMultiReason("STOCK_LOW", "stock low", http.StatusConflict, codes.FailedPrecondition)
+)
+
+func TestStatusRoundTripKeepsMetadataAndCauses(t *testing.T) {
+	inner := xerr.With(xerr.New(reasonStockLow, nil), "sku", "A-1")
+	err := xerr.WithAttrs(xerr.Wrap(inner, reasonOrderFailed),
+		slog.String("order_id", "o-42"),
+		xerr.Internal("db_host", "10.0.0.1"))
+
+	st := ErrorToGRPCStatus(err, WithCauses())
+	var pr *ProtoReason
+	for _, d := range st.Details() {
+		if p, ok := d.(*ProtoReason); ok {
+			pr = p
+		}
+	}
+	if pr == nil {
+		t.Fatal("expected ProtoReason detail")
+	}
+	if _, leaked := pr.GetMetadata()["db_host"]; leaked {
+		t.Fatalf("internal metadata leaked: %v", pr.GetMetadata())
+	}
+	if len(pr.GetCauses()) != 1 || pr.GetCauses()[0].GetErrorCode() != "STOCK_LOW" {
+		t.Fatalf("unexpected causes: %v", pr.GetCauses())
+	}
+
+	got := FromGRPCStatus(st)
+	if got.Reason().Code() != "ORDER_FAILED" {
+		t.Fatalf("unexpected code %q", got.Reason().Code())
+	}
+	if got.Metadata()["order_id"] != "o-42" || got.Metadata()["sku"] != "A-1" {
+		t.Fatalf("unexpected metadata %v", got.Metadata())
+	}
+	if !errors.Is(got, reasonStockLow) {
+		t.Fatal("expected the cause reason to be restored")
+	}
+}
+
+func TestStatusOmitsCausesByDefault(t *testing.T) {
+	inner := xerr.With(xerr.New(reasonStockLow, nil), "sku", "A-1")
+	st := ErrorToGRPCStatus(xerr.Wrap(inner, reasonOrderFailed))
+	for _, d := range st.Details() {
+		if p, ok := d.(*ProtoReason); ok && len(p.GetCauses()) > 0 {
+			t.Fatalf("expected no causes, got %v", p.GetCauses())
+		}
+	}
+
+	got := FromGRPCStatus(st)
+	if got.Reason().Code() != "ORDER_FAILED" || errors.Is(got, reasonStockLow) {
+		t.Fatalf("expected only the outer reason, got %v", xerr.Reasons(got))
+	}
+	if got.Metadata()["sku"] != "A-1" {
+		t.Fatalf("expected public metadata to be kept, got %v", got.Metadata())
+	}
+}
+
+func TestStatusCarriesStandardDetails(t *testing.T) {
+	err := xerr.With(xerr.NewViolations().Add("email", "required", "email is required").Err(),
+		KeyRetryDelay, 2*time.Second)
+
+	st := ErrorToGRPCStatus(err)
+	var (
+		info  *errdetails.ErrorInfo
+		br    *errdetails.BadRequest
+		retry *errdetails.RetryInfo
+	)
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.BadRequest:
+			br = d
+		case *errdetails.RetryInfo:
+			retry = d
+		}
+	}
+	if info == nil || info.GetReason() != "VALIDATION_FAILED" {
+		t.Fatalf("unexpected ErrorInfo %v", info)
+	}
+	if br == nil || len(br.GetFieldViolations()) != 1 || br.GetFieldViolations()[0].GetField() != "email" {
+		t.Fatalf("unexpected BadRequest %v", br)
+	}
+	if retry == nil || retry.GetRetryDelay().AsDuration() != 2*time.Second {
+		t.Fatalf("unexpected RetryInfo %v", retry)
+	}
+
+	got := FromGRPCStatus(st)
+	if fvs := xerr.FieldViolations(got); len(fvs) != 1 || fvs[0].Constraint != "required" {
+		t.Fatalf("unexpected violations %v", fvs)
+	}
+}
+
+func TestFromGRPCStatusUnderstandsErrorInfo(t *testing.T) {
+	st, err := status.New(codes.NotFound, "no such user").WithDetails(
+		&errdetails.ErrorInfo{Reason: "USER_NOT_FOUND", Domain: "users.example.com", Metadata: map[string]string{"user_id": "7"}},
+		&errdetails.RetryInfo{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := FromGRPCStatus(st)
+	if got.Reason().Code() != "USER_NOT_FOUND" || GetGRPCCode(got.Reason()) != codes.NotFound {
+		t.Fatalf("unexpected reason %v", got.Reason())
+	}
+	if got.Metadata()["user_id"] != "7" {
+		t.Fatalf("unexpected metadata %v", got.Metadata())
+	}
+}
+
+var reasonQuotaExceeded = xerr.NewClassifiedReason("GERR_TEST_QUOTA_EXCEEDED", "quota exceeded", http.StatusTooManyRequests,
+	xerr.Classification{Retryable: true, Severity: xerr.SeverityWarning})
+
+func init() {
+	xerr.MustRegister(reasonQuotaExceeded)
+}
+
+func TestFromGRPCStatusRestoresRegisteredReason(t *testing.T) {
+	err := xerr.Wrap(xerr.With(xerr.New(reasonQuotaExceeded, nil), "tenant", "acme"), reasonOrderFailed)
+
+	got := FromGRPCStatus(ErrorToGRPCStatus(err, WithCauses()))
+	if _, ok := got.Reason().(*ProtoReason); !ok {
+		t.Fatalf("expected unregistered code to stay a ProtoReason, got %T", got.Reason())
+	}
+	quota, ok := xerr.AsReason[*xerr.ClassifiedReason](got)
+	if !ok || quota != reasonQuotaExceeded {
+		t.Fatalf("expected the registered reason in the chain, got %v", xerr.Reasons(got))
+	}
+	if !errors.Is(got, reasonQuotaExceeded) || !xerr.IsRetryable(got.Unwrap()) {
+		t.Fatal("expected the restored cause to keep its classification")
+	}
+	if got.Metadata()["tenant"] != "acme" {
+		t.Fatalf("expected remote metadata, got %v", got.Metadata())
+	}
+
+	direct := FromGRPCStatus(ErrorToGRPCStatus(xerr.With(xerr.New(reasonQuotaExceeded, nil), "tenant", "acme")))
+	if direct.Reason() != reasonQuotaExceeded || direct.Metadata()["tenant"] != "acme" {
+		t.Fatalf("unexpected decoded error %q with reason %T", direct.Error(), direct.Reason())
+	}
+}
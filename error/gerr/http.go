@@ -0,0 +1,201 @@
+package gerr
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// JSONContentType is the media type of Connect and grpc-gateway error bodies.
+const JSONContentType = "application/json"
+
+const typeURLPrefix = "type.googleapis.com/"
+
+// ConnectError is the JSON error body of the Connect protocol. Details carry
+// the same messages as ErrorToGRPCStatus, ProtoReason included.
+type ConnectError struct {
+	Code    string               `json:"code"`
+	Message string               `json:"message,omitempty"`
+	Details []ConnectErrorDetail `json:"details,omitempty"`
+}
+
+// ConnectErrorDetail is a protobuf message encoded as in the Connect protocol:
+// the fully-qualified message name and its base64 binary encoding.
+type ConnectErrorDetail struct {
+	Type  string          `json:"type"`
+	Value string          `json:"value"`
+	Debug json.RawMessage `json:"debug,omitempty"`
+}
+
+// ToConnectError renders err in the Connect error JSON shape.
+func ToConnectError(err xerr.Error, opts ...StatusOption) *ConnectError {
+	st := ErrorToGRPCStatus(err, opts...).Proto()
+	ce := &ConnectError{
+		Code:    connectCode(codes.Code(st.GetCode())),
+		Message: st.GetMessage(),
+	}
+	for _, detail := range st.GetDetails() {
+		ce.Details = append(ce.Details, ConnectErrorDetail{
+			Type:  strings.TrimPrefix(detail.GetTypeUrl(), typeURLPrefix),
+			Value: base64.RawStdEncoding.EncodeToString(detail.GetValue()),
+		})
+	}
+	return ce
+}
+
+// Err converts the Connect error back into xerr.Error like FromGRPCStatus.
+// Details that cannot be decoded are skipped.
+func (c *ConnectError) Err() xerr.Error {
+	st := &spb.Status{
+		Code:    int32(connectCodeFrom(c.Code)),
+		Message: c.Message,
+	}
+	for _, d := range c.Details {
+		// Connect allows both padded and unpadded base64
+		value, decodeErr := base64.RawStdEncoding.DecodeString(strings.TrimRight(d.Value, "="))
+		if decodeErr != nil {
+			continue
+		}
+		st.Details = append(st.Details, &anypb.Any{TypeUrl: typeURLPrefix + d.Type, Value: value})
+	}
+	return fromStatus(status.FromProto(st), nil, xerr.WithoutStack())
+}
+
+// WriteConnectError writes err as a Connect error response.
+func WriteConnectError(w http.ResponseWriter, err xerr.Error, opts ...StatusOption) error {
+	body, mErr := json.Marshal(ToConnectError(err, opts...))
+	if mErr != nil {
+		return mErr
+	}
+	return writeJSON(w, HTTPFromGRPC(ErrorToGRPCCode(err)), body)
+}
+
+// ParseConnectError reads a Connect error response and returns the Error it describes.
+func ParseConnectError(resp *http.Response) (xerr.Error, error) {
+	body, err := readJSON(resp)
+	if err != nil {
+		return nil, err
+	}
+	var ce ConnectError
+	if err := json.Unmarshal(body, &ce); err != nil {
+		return nil, fmt.Errorf("gerr: invalid connect error: %w", err)
+	}
+	return ce.Err(), nil
+}
+
+// MarshalGatewayStatus renders err as the google.rpc.Status JSON that
+// grpc-gateway returns, with ProtoReason as an Any detail.
+func MarshalGatewayStatus(err xerr.Error, opts ...StatusOption) ([]byte, error) {
+	return protojson.Marshal(ErrorToGRPCStatus(err, opts...).Proto())
+}
+
+// UnmarshalGatewayStatus parses google.rpc.Status JSON back into xerr.Error.
+// Details whose type is not linked into the binary are dropped.
+func UnmarshalGatewayStatus(data []byte) (xerr.Error, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("gerr: invalid status: %w", err)
+	}
+	var details []json.RawMessage
+	if raw, ok := fields["details"]; ok {
+		if err := json.Unmarshal(raw, &details); err != nil {
+			return nil, fmt.Errorf("gerr: invalid status details: %w", err)
+		}
+		delete(fields, "details")
+	}
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var st spb.Status
+	opts := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err := opts.Unmarshal(rest, &st); err != nil {
+		return nil, fmt.Errorf("gerr: invalid status: %w", err)
+	}
+	// Decode details one by one so a single unresolvable @type does not
+	// lose the rest of the error.
+	for _, raw := range details {
+		detail := new(anypb.Any)
+		if opts.Unmarshal(raw, detail) == nil {
+			st.Details = append(st.Details, detail)
+		}
+	}
+	return fromStatus(status.FromProto(&st), nil, xerr.WithoutStack()), nil
+}
+
+// WriteGatewayStatus writes err as a grpc-gateway error response.
+func WriteGatewayStatus(w http.ResponseWriter, err xerr.Error, opts ...StatusOption) error {
+	body, mErr := MarshalGatewayStatus(err, opts...)
+	if mErr != nil {
+		return mErr
+	}
+	return writeJSON(w, HTTPFromGRPC(ErrorToGRPCCode(err)), body)
+}
+
+// ParseGatewayStatus reads a grpc-gateway error response and returns the
+// Error it describes.
+func ParseGatewayStatus(resp *http.Response) (xerr.Error, error) {
+	body, err := readJSON(resp)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalGatewayStatus(body)
+}
+
+func writeJSON(w http.ResponseWriter, httpStatus int, body []byte) error {
+	w.Header().Set("Content-Type", JSONContentType)
+	w.WriteHeader(httpStatus)
+	_, err := w.Write(body)
+	return err
+}
+
+func readJSON(resp *http.Response) ([]byte, error) {
+	if resp == nil || resp.Body == nil {
+		return nil, errors.New("gerr: empty response")
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != JSONContentType {
+		return nil, fmt.Errorf("gerr: unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// connectCode returns the Connect name of code, e.g. "not_found".
+func connectCode(code codes.Code) string {
+	if code == codes.OK || code > codes.Unauthenticated {
+		return "unknown"
+	}
+	var b strings.Builder
+	for i, r := range code.String() {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func connectCodeFrom(name string) codes.Code {
+	for code := codes.Canceled; code <= codes.Unauthenticated; code++ {
+		if connectCode(code) == name {
+			return code
+		}
+	}
+	return codes.Unknown
+}
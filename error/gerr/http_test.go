@@ -0,0 +1,106 @@
+package gerr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	"google.golang.org/grpc/codes"
+)
+
+func TestConnectErrorRoundTrip(t *testing.T) {
+	err := xerr.With(xerr.New(reasonStockLow, nil), "sku", "A-1")
+
+	rec := httptest.NewRecorder()
+	if wErr := WriteConnectError(rec, err); wErr != nil {
+		t.Fatal(wErr)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for failed_precondition, got %d", rec.Code)
+	}
+
+	var body map[string]any
+	if jErr := json.Unmarshal(rec.Body.Bytes(), &body); jErr != nil {
+		t.Fatal(jErr)
+	}
+	if body["code"] != "failed_precondition" {
+		t.Fatalf("unexpected connect code %v", body["code"])
+	}
+	details, _ := body["details"].([]any)
+	if len(details) == 0 || details[0].(map[string]any)["type"] != "gerr.ProtoReason" {
+		t.Fatalf("expected ProtoReason detail, got %v", body["details"])
+	}
+
+	got, pErr := ParseConnectError(rec.Result())
+	if pErr != nil {
+		t.Fatal(pErr)
+	}
+	if got.Reason().Code() != "STOCK_LOW" || got.Metadata()["sku"] != "A-1" {
+		t.Fatalf("unexpected error %v with metadata %v", got, got.Metadata())
+	}
+}
+
+func TestGatewayStatusRoundTrip(t *testing.T) {
+	err := xerr.NewViolations().Add("email", "required", "email is required").Err()
+
+	rec := httptest.NewRecorder()
+	if wErr := WriteGatewayStatus(rec, err); wErr != nil {
+		t.Fatal(wErr)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	var body struct {
+		Details []map[string]any `json:"details"`
+	}
+	if jErr := json.Unmarshal(rec.Body.Bytes(), &body); jErr != nil {
+		t.Fatal(jErr)
+	}
+	if len(body.Details) == 0 || body.Details[0]["@type"] != "type.googleapis.com/gerr.ProtoReason" {
+		t.Fatalf("expected ProtoReason Any detail, got %s", rec.Body.String())
+	}
+
+	got, pErr := ParseGatewayStatus(rec.Result())
+	if pErr != nil {
+		t.Fatal(pErr)
+	}
+	if got.Reason().Code() != xerr.ReasonValidationFailed.Code() || len(xerr.FieldViolations(got)) != 1 {
+		t.Fatalf("unexpected error %v", got)
+	}
+}
+
+func TestUnmarshalGatewayStatusSkipsUnknownDetails(t *testing.T) {
+	body := `{
+		"code": 9,
+		"message": "stock is low",
+		"details": [
+			{"@type": "type.googleapis.com/acme.Custom", "value": "x"},
+			{"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "STOCK_LOW", "domain": "shop", "metadata": {"sku": "A-1"}}
+		]
+	}`
+
+	got, err := UnmarshalGatewayStatus([]byte(body))
+	if err != nil {
+		t.Fatalf("expected unknown details to be skipped, got %v", err)
+	}
+	if got.Reason().Code() != "STOCK_LOW" || got.Metadata()["sku"] != "A-1" {
+		t.Fatalf("unexpected error %v with metadata %v", got, got.Metadata())
+	}
+	if ErrorToGRPCCode(got) != codes.FailedPrecondition {
+		t.Fatalf("unexpected code %v", ErrorToGRPCCode(got))
+	}
+}
+
+func TestConnectCodes(t *testing.T) {
+	if got := connectCode(codes.DeadlineExceeded); got != "deadline_exceeded" {
+		t.Fatalf("unexpected name %q", got)
+	}
+	if got := connectCodeFrom("permission_denied"); got != codes.PermissionDenied {
+		t.Fatalf("unexpected code %v", got)
+	}
+	if got := connectCodeFrom("bogus"); got != codes.Unknown {
+		t.Fatalf("unexpected code %v", got)
+	}
+}
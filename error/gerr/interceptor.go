@@ -0,0 +1,137 @@
+package gerr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	"google.golang.org/grpc"
+)
+
+// ErrorHook observes every error an interceptor translates, e.g. to log it or
+// count it by code. method is the full gRPC method name.
+type ErrorHook func(ctx context.Context, method string, err xerr.Error)
+
+type interceptorConfig struct {
+	hooks         []ErrorHook
+	recoverPanics bool
+	statusOpts    []StatusOption
+}
+
+type InterceptorOption func(*interceptorConfig)
+
+// WithErrorHook adds a hook called with each translated error.
+func WithErrorHook(hook ErrorHook) InterceptorOption {
+	return func(c *interceptorConfig) {
+		if hook != nil {
+			c.hooks = append(c.hooks, hook)
+		}
+	}
+}
+
+// WithPanicRecovery controls whether server interceptors turn panics into
+// INTERNAL_PANIC errors. It is enabled by default.
+func WithPanicRecovery(enabled bool) InterceptorOption {
+	return func(c *interceptorConfig) { c.recoverPanics = enabled }
+}
+
+// WithStatusOptions sets the options server interceptors use to build
+// statuses, e.g. WithCauses.
+func WithStatusOptions(opts ...StatusOption) InterceptorOption {
+	return func(c *interceptorConfig) { c.statusOpts = append(c.statusOpts, opts...) }
+}
+
+func newInterceptorConfig(opts []InterceptorOption) *interceptorConfig {
+	cfg := &interceptorConfig{recoverPanics: true}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+func (c *interceptorConfig) notify(ctx context.Context, method string, err error) {
+	if len(c.hooks) == 0 {
+		return
+	}
+	var x xerr.Error
+	if !errors.As(err, &x) {
+		return
+	}
+	for _, hook := range c.hooks {
+		hook(ctx, method, x)
+	}
+}
+
+// serverError runs the hooks and converts err into a status error.
+func (c *interceptorConfig) serverError(ctx context.Context, method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	c.notify(ctx, method, err)
+	return toStatusError(err, c.statusOpts...)
+}
+
+// UnaryServerInterceptor converts xerr.Error values returned by handlers, and
+// panics raised by them, into gRPC statuses carrying a ProtoReason detail.
+func UnaryServerInterceptor(opts ...InterceptorOption) grpc.UnaryServerInterceptor {
+	cfg := newInterceptorConfig(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		if cfg.recoverPanics {
+			defer func() {
+				if v := recover(); v != nil {
+					resp, err = nil, cfg.serverError(ctx, info.FullMethod, xerr.FromPanic(v))
+				}
+			}()
+		}
+		resp, err = handler(ctx, req)
+		return resp, cfg.serverError(ctx, info.FullMethod, err)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor.
+// Handlers receive a *ServerStream, so errors report the stream progress and
+// their metadata is also sent as trailers.
+func StreamServerInterceptor(opts ...InterceptorOption) grpc.StreamServerInterceptor {
+	cfg := newInterceptorConfig(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := ss.Context()
+		ws := WrapServerStream(ss)
+		ws.statusOpts = cfg.statusOpts
+		if cfg.recoverPanics {
+			defer func() {
+				if v := recover(); v != nil {
+					err = cfg.serverError(ctx, info.FullMethod, ws.annotate(xerr.FromPanic(v)))
+				}
+			}()
+		}
+		return cfg.serverError(ctx, info.FullMethod, ws.annotate(handler(srv, ws)))
+	}
+}
+
+// UnaryClientInterceptor turns status errors returned by the server back into
+// xerr.Error values; see FromError.
+func UnaryClientInterceptor(opts ...InterceptorOption) grpc.UnaryClientInterceptor {
+	cfg := newInterceptorConfig(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		err := FromError(invoker(ctx, method, req, reply, cc, callOpts...))
+		if err != nil {
+			cfg.notify(ctx, method, err)
+		}
+		return err
+	}
+}
+
+// StreamClientInterceptor turns status errors from opening a stream, and
+// from the returned *ClientStream, into xerr.Error values.
+func StreamClientInterceptor(opts ...InterceptorOption) grpc.StreamClientInterceptor {
+	cfg := newInterceptorConfig(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			err = FromError(err)
+			cfg.notify(ctx, method, err)
+			return nil, err
+		}
+		return &ClientStream{ClientStream: cs, ctx: ctx, method: method, cfg: cfg}, nil
+	}
+}
@@ -0,0 +1,182 @@
+package gerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var reasonNotServing = NewMultiReason("NOT_SERVING", "service not serving", http.StatusServiceUnavailable, codes.Unavailable)
+
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	check func() error
+	watch func(healthpb.Health_WatchServer) error
+}
+
+func (s *healthServer) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *healthServer) Watch(_ *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	if s.watch != nil {
+		return s.watch(stream)
+	}
+	return s.check()
+}
+
+func dialHealth(t *testing.T, check func() error, serverOpts, clientOpts []InterceptorOption, dialOpts ...grpc.DialOption) healthpb.HealthClient {
+	t.Helper()
+	return dialHealthServer(t, &healthServer{check: check}, serverOpts, clientOpts, dialOpts...)
+}
+
+func dialHealthServer(t *testing.T, hs healthpb.HealthServer, serverOpts, clientOpts []InterceptorOption, dialOpts ...grpc.DialOption) healthpb.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(serverOpts...)),
+		grpc.StreamInterceptor(StreamServerInterceptor(serverOpts...)),
+	)
+	healthpb.RegisterHealthServer(srv, hs)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	dialOpts = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(clientOpts...)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(clientOpts...)),
+	}, dialOpts...)
+	conn, err := grpc.NewClient("passthrough:///bufnet", dialOpts...)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryInterceptorsRoundTrip(t *testing.T) {
+	var serverSeen, clientSeen xerr.ErrorCode
+	client := dialHealth(t,
+		func() error { return xerr.With(xerr.New(reasonNotServing, nil), "db", "primary") },
+		[]InterceptorOption{WithErrorHook(func(_ context.Context, _ string, err xerr.Error) { serverSeen = err.Reason().Code() })},
+		[]InterceptorOption{WithErrorHook(func(_ context.Context, _ string, err xerr.Error) { clientSeen = err.Reason().Code() })},
+	)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", status.Code(err))
+	}
+	if !errors.Is(err, reasonNotServing) {
+		t.Fatalf("expected client error to match reason, got %v", err)
+	}
+	var x xerr.Error
+	if !errors.As(err, &x) || xerr.GetHTTPCode(x.Reason()) != http.StatusServiceUnavailable {
+		t.Fatalf("expected xerr.Error with HTTP 503, got %#v", err)
+	}
+	if serverSeen != "NOT_SERVING" || clientSeen != "NOT_SERVING" {
+		t.Fatalf("hooks saw server=%q client=%q", serverSeen, clientSeen)
+	}
+}
+
+func TestUnaryServerInterceptorSendsCausesOnlyWhenAsked(t *testing.T) {
+	check := func() error { return xerr.Wrap(xerr.New(reasonStockLow, nil), reasonNotServing) }
+
+	_, err := dialHealth(t, check, nil, nil).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if !errors.Is(err, reasonNotServing) || errors.Is(err, reasonStockLow) {
+		t.Fatalf("expected causes to stay on the server, got %v", err)
+	}
+
+	withCauses := []InterceptorOption{WithStatusOptions(WithCauses())}
+	_, err = dialHealth(t, check, withCauses, nil).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if !errors.Is(err, reasonStockLow) {
+		t.Fatalf("expected the cause to reach the client, got %v", err)
+	}
+}
+
+func TestUnaryServerInterceptorRecoversPanic(t *testing.T) {
+	client := dialHealth(t, func() error { panic("secret=abc") }, nil, nil)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", status.Code(err))
+	}
+	if !xerr.HasCode(err, xerr.ReasonInternalPanic.Code()) {
+		t.Fatalf("expected INTERNAL_PANIC, got %v", err)
+	}
+	assertNoPanicValue(t, err, nil)
+}
+
+func TestStreamServerInterceptorRecoversPanic(t *testing.T) {
+	client := dialHealthServer(t, &healthServer{watch: func(healthpb.Health_WatchServer) error { panic("secret=abc") }}, nil, nil)
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	_, err = stream.Recv()
+	if !xerr.HasCode(err, xerr.ReasonInternalPanic.Code()) {
+		t.Fatalf("expected INTERNAL_PANIC, got %s", err)
+	}
+	assertNoPanicValue(t, err, stream.Trailer())
+}
+
+// assertNoPanicValue checks that nothing the client received mentions the panic value.
+func assertNoPanicValue(t *testing.T, err error, trailer metadata.MD) {
+	t.Helper()
+	var x xerr.Error
+	if !errors.As(err, &x) {
+		t.Fatalf("expected xerr.Error, got %T", err)
+	}
+	seen := []string{err.Error(), fmt.Sprint(x.Metadata()), fmt.Sprint(trailer)}
+	for _, detail := range status.Convert(err).Details() {
+		seen = append(seen, fmt.Sprint(detail))
+	}
+	for _, s := range seen {
+		if strings.Contains(s, "abc") {
+			t.Fatalf("panic value reached the client: %s", s)
+		}
+	}
+}
+
+func TestStreamInterceptorsRoundTrip(t *testing.T) {
+	client := dialHealth(t, func() error { return xerr.New(reasonNotServing, nil) }, nil, nil)
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	_, err = stream.Recv()
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if reason := FromGRPCStatus(status.Convert(err)).Reason(); reason.Code() != "NOT_SERVING" {
+		t.Fatalf("expected NOT_SERVING detail, got %q", reason.Code())
+	}
+}
+
+func TestFromErrorPassesThroughPlainErrors(t *testing.T) {
+	plain := errors.New("plain")
+	if FromError(plain) != plain {
+		t.Fatal("expected plain error unchanged")
+	}
+	if FromError(nil) != nil {
+		t.Fatal("expected nil")
+	}
+}
@@ -1,6 +1,8 @@
 package gerr
 
 import (
+	"net/http"
+
 	"github.com/nduyhai/xcore/error/xerr"
 	"google.golang.org/grpc/codes"
 )
@@ -11,7 +13,7 @@ type MultiReason struct {
 	GrpcCode   codes.Code `json:"grpc_code,omitempty"`
 }
 
-func NewMultiReason(code xerr.ErrorCode, message string, httpStatus int, grpcCode codes.Code) xerr.Reason {
+func NewMultiReason(code xerr.ErrorCode, message string, httpStatus int, grpcCode codes.Code) *MultiReason {
 	return &MultiReason{
 		SimpleReason: xerr.SimpleReason{
 			ErrorCode:    code,
@@ -22,18 +24,48 @@ func NewMultiReason(code xerr.ErrorCode, message string, httpStatus int, grpcCod
 	}
 }
 
+// HTTPCode returns the HTTP status, derived from the gRPC code when unset
 func (r *MultiReason) HTTPCode() int {
+	if r.StatusCode == 0 {
+		return reasonHTTPFromGRPC(r.GrpcCode)
+	}
 	return r.StatusCode
 }
 
+// GRPCCode returns the gRPC code, derived from the HTTP status when unset
 func (r *MultiReason) GRPCCode() codes.Code {
+	if r.GrpcCode == codes.OK && r.StatusCode != 0 {
+		return GRPCFromHTTP(r.StatusCode)
+	}
 	return r.GrpcCode
 }
 
+// GetGRPCCode returns the gRPC code of reason. A reason describes an error,
+// so the result is never codes.OK.
 func GetGRPCCode(reason xerr.Reason) codes.Code {
+	if code := reasonGRPCCode(reason); code != codes.OK {
+		return code
+	}
+	return codes.Unknown
+}
+
+// reasonHTTPFromGRPC derives the HTTP status of an error reason from its gRPC
+// code. A reason describes an error, so statuses below 400 become 500.
+func reasonHTTPFromGRPC(code codes.Code) int {
+	if status := HTTPFromGRPC(code); status >= http.StatusBadRequest {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+func reasonGRPCCode(reason xerr.Reason) codes.Code {
 	if grpcReason, ok := reason.(GRPCAware); ok {
 		return grpcReason.GRPCCode()
 	}
+	// Derive the code from the HTTP status through the code mapping
+	if httpReason, ok := reason.(xerr.HTTPAware); ok {
+		return GRPCFromHTTP(httpReason.HTTPCode())
+	}
 	// Default fallback
 	return codes.Unknown
 }
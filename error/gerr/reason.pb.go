@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.9
+// 	protoc-gen-go v1.36.11
 // 	protoc        (unknown)
 // source: reason.proto
 
@@ -31,7 +31,11 @@ type ProtoReason struct {
 	// http_code is the optional HTTP status code
 	HttpCode *int32 `protobuf:"varint,3,opt,name=http_code,json=httpCode,proto3,oneof" json:"http_code,omitempty"`
 	// grpc_code is the optional GRPC status code
-	GrpcCode      *int32 `protobuf:"varint,4,opt,name=grpc_code,json=grpcCode,proto3,oneof" json:"grpc_code,omitempty"`
+	GrpcCode *int32 `protobuf:"varint,4,opt,name=grpc_code,json=grpcCode,proto3,oneof" json:"grpc_code,omitempty"`
+	// metadata holds the public metadata of the error
+	Metadata map[string]string `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// causes lists the reasons of the wrapped errors, outermost first
+	Causes        []*ProtoReason `protobuf:"bytes,6,rep,name=causes,proto3" json:"causes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -94,17 +98,36 @@ func (x *ProtoReason) GetGrpcCode() int32 {
 	return 0
 }
 
+func (x *ProtoReason) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ProtoReason) GetCauses() []*ProtoReason {
+	if x != nil {
+		return x.Causes
+	}
+	return nil
+}
+
 var File_reason_proto protoreflect.FileDescriptor
 
 const file_reason_proto_rawDesc = "" +
 	"\n" +
-	"\freason.proto\x12\x04gerr\"\xb1\x01\n" +
+	"\freason.proto\x12\x04gerr\"\xd6\x02\n" +
 	"\vProtoReason\x12\x1d\n" +
 	"\n" +
 	"error_code\x18\x01 \x01(\tR\terrorCode\x12#\n" +
 	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\x12 \n" +
 	"\thttp_code\x18\x03 \x01(\x05H\x00R\bhttpCode\x88\x01\x01\x12 \n" +
-	"\tgrpc_code\x18\x04 \x01(\x05H\x01R\bgrpcCode\x88\x01\x01B\f\n" +
+	"\tgrpc_code\x18\x04 \x01(\x05H\x01R\bgrpcCode\x88\x01\x01\x12;\n" +
+	"\bmetadata\x18\x05 \x03(\v2\x1f.gerr.ProtoReason.MetadataEntryR\bmetadata\x12)\n" +
+	"\x06causes\x18\x06 \x03(\v2\x11.gerr.ProtoReasonR\x06causes\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\f\n" +
 	"\n" +
 	"_http_codeB\f\n" +
 	"\n" +
@@ -123,16 +146,19 @@ func file_reason_proto_rawDescGZIP() []byte {
 	return file_reason_proto_rawDescData
 }
 
-var file_reason_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_reason_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_reason_proto_goTypes = []any{
 	(*ProtoReason)(nil), // 0: gerr.ProtoReason
+	nil,                 // 1: gerr.ProtoReason.MetadataEntry
 }
 var file_reason_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: gerr.ProtoReason.metadata:type_name -> gerr.ProtoReason.MetadataEntry
+	0, // 1: gerr.ProtoReason.causes:type_name -> gerr.ProtoReason
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_reason_proto_init() }
@@ -147,7 +173,7 @@ func file_reason_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reason_proto_rawDesc), len(file_reason_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
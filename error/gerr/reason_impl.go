@@ -20,11 +20,19 @@ func (p *ProtoReason) Message() string {
 	return p.ErrorMessage
 }
 
+// Error lets a ProtoReason be used as an errors.Is target
+func (p *ProtoReason) Error() string {
+	return p.ErrorCode + ": " + p.ErrorMessage
+}
+
 // HTTPCode implements xerr.HTTPAware interface
 func (p *ProtoReason) HTTPCode() int {
 	if p.HttpCode != nil {
 		return int(*p.HttpCode)
 	}
+	if p.GrpcCode != nil {
+		return reasonHTTPFromGRPC(codes.Code(*p.GrpcCode))
+	}
 	// Default fallback
 	return http.StatusInternalServerError
 }
@@ -34,6 +42,9 @@ func (p *ProtoReason) GRPCCode() codes.Code {
 	if p.GrpcCode != nil {
 		return codes.Code(*p.GrpcCode)
 	}
+	if p.HttpCode != nil {
+		return GRPCFromHTTP(int(*p.HttpCode))
+	}
 	// Default fallback
 	return codes.Unknown
 }
@@ -0,0 +1,118 @@
+package gerr
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	"google.golang.org/grpc"
+)
+
+type retryConfig struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	permanent   []xerr.ErrorCode
+}
+
+type RetryOption func(*retryConfig)
+
+// WithMaxAttempts sets the total number of attempts, the first call
+// included. The default is 3.
+func WithMaxAttempts(n int) RetryOption {
+	return func(c *retryConfig) {
+		if n > 0 {
+			c.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the exponential backoff bounds. Each wait is drawn
+// uniformly between zero and min(max, base*2^attempt). The defaults are
+// 100ms and 5s.
+func WithBackoff(base, max time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		if base > 0 {
+			c.baseDelay = base
+		}
+		if max >= base {
+			c.maxDelay = max
+		}
+	}
+}
+
+// WithPermanentCodes marks reason codes that are never retried, even when the
+// reason or the server says otherwise.
+func WithPermanentCodes(codes ...xerr.ErrorCode) RetryOption {
+	return func(c *retryConfig) { c.permanent = append(c.permanent, codes...) }
+}
+
+// RetryUnaryClientInterceptor retries failed unary calls. A call is retried
+// when the server attached google.rpc.RetryInfo or when xerr.IsRetryable
+// holds for the decoded error, unless its chain contains a permanent code.
+// The wait is the jittered backoff, or the RetryInfo delay when longer.
+// The error of the last attempt is returned unchanged.
+func RetryUnaryClientInterceptor(opts ...RetryOption) grpc.UnaryClientInterceptor {
+	cfg := &retryConfig{maxAttempts: 3, baseDelay: 100 * time.Millisecond, maxDelay: 5 * time.Second}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		var err error
+		for attempt := 0; attempt < cfg.maxAttempts; attempt++ {
+			if attempt > 0 {
+				if waitErr := sleepCtx(ctx, cfg.wait(attempt, err)); waitErr != nil {
+					return err
+				}
+			}
+			err = invoker(ctx, method, req, reply, cc, callOpts...)
+			if err == nil || !cfg.retryable(err) {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+func (c *retryConfig) retryable(err error) bool {
+	x, ok := FromError(err).(xerr.Error)
+	if !ok {
+		return false
+	}
+	for _, code := range c.permanent {
+		if xerr.HasCode(x, code) {
+			return false
+		}
+	}
+	if _, hinted := retryDelay(x); hinted {
+		return true
+	}
+	return xerr.IsRetryable(x)
+}
+
+// wait returns the delay before the given attempt, err being the previous failure.
+func (c *retryConfig) wait(attempt int, err error) time.Duration {
+	ceiling := c.maxDelay
+	if shift := attempt - 1; shift < 32 && c.baseDelay<<shift < c.maxDelay && c.baseDelay<<shift > 0 {
+		ceiling = c.baseDelay << shift
+	}
+	delay := rand.N(ceiling + 1)
+	if x, ok := FromError(err).(xerr.Error); ok {
+		if hinted, hasHint := retryDelay(x); hasHint && hinted > delay {
+			delay = hinted
+		}
+	}
+	return delay
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+package gerr
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryInfoFollowsClassification(t *testing.T) {
+	retryable := xerr.New(xerr.NewHTTPReason("BUSY", "busy", http.StatusServiceUnavailable), nil)
+	if ri := RetryInfo(retryable); ri == nil || ri.GetRetryDelay().AsDuration() != 0 {
+		t.Fatalf("expected zero-delay RetryInfo, got %v", ri)
+	}
+	if ri := RetryInfo(xerr.With(retryable, KeyRetryDelay, "3s")); ri.GetRetryDelay().AsDuration() != 3*time.Second {
+		t.Fatalf("expected 3s delay, got %v", ri)
+	}
+	if ri := RetryInfo(xerr.New(reasonOrderFailed, nil)); ri != nil {
+		t.Fatalf("expected no RetryInfo for a conflict, got %v", ri)
+	}
+}
+
+func dialRetrying(t *testing.T, check func() error, opts ...RetryOption) healthpb.HealthClient {
+	return dialHealth(t, check, nil, nil,
+		grpc.WithChainUnaryInterceptor(RetryUnaryClientInterceptor(append([]RetryOption{WithBackoff(time.Millisecond, 5*time.Millisecond)}, opts...)...)))
+}
+
+func TestRetryInterceptorRetriesRetryableErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := dialRetrying(t, func() error {
+		if calls.Add(1) < 3 {
+			return xerr.New(reasonQuotaExceeded, nil)
+		}
+		return nil
+	})
+
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetryInterceptorStopsOnPermanentErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := dialRetrying(t, func() error {
+		calls.Add(1)
+		return xerr.New(reasonQuotaExceeded, nil)
+	}, WithMaxAttempts(5), WithPermanentCodes(reasonQuotaExceeded.Code()))
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if !xerr.HasCode(err, reasonQuotaExceeded.Code()) || calls.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d (%v)", calls.Load(), err)
+	}
+
+	calls.Store(0)
+	client = dialRetrying(t, func() error {
+		calls.Add(1)
+		return xerr.New(reasonOrderFailed, nil)
+	}, WithMaxAttempts(5))
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err == nil || calls.Load() != 1 {
+		t.Fatalf("expected non-retryable error after one attempt, got %d (%v)", calls.Load(), err)
+	}
+}
+
+func TestRetryInterceptorHonoursRetryInfo(t *testing.T) {
+	var calls atomic.Int32
+	client := dialRetrying(t, func() error {
+		calls.Add(1)
+		st, _ := status.New(codes.Aborted, "try later").WithDetails(&errdetails.RetryInfo{})
+		if calls.Load() == 1 {
+			return xerr.With(xerr.New(reasonOrderFailed, nil), KeyRetryDelay, 30*time.Millisecond)
+		}
+		return st.Err()
+	}, WithMaxAttempts(3))
+
+	start := time.Now()
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Aborted || calls.Load() != 3 {
+		t.Fatalf("expected 3 attempts ending in Aborted, got %d (%v)", calls.Load(), err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Fatalf("expected the RetryInfo delay to be honoured, took %v", elapsed)
+	}
+}
@@ -0,0 +1,207 @@
+package gerr
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TrailerPrefix prefixes the trailer keys that carry error metadata.
+const TrailerPrefix = "xerr-"
+
+// Metadata keys recording how far a stream got before it failed.
+const (
+	KeyMessagesSent     = "messages_sent"
+	KeyMessagesReceived = "messages_received"
+)
+
+// TrailerMetadata returns the public metadata of err as gRPC trailers. Keys
+// are lower-cased and prefixed with TrailerPrefix; values that are not
+// printable ASCII are sent as binary "-bin" entries.
+func TrailerMetadata(err xerr.Error) metadata.MD {
+	md := metadata.MD{}
+	if err == nil {
+		return md
+	}
+	for key, value := range xerr.PublicMetadata(err) {
+		key = TrailerPrefix + trailerKey(key)
+		if !printableASCII(value) {
+			key += "-bin"
+		}
+		md.Append(key, value)
+	}
+	return md
+}
+
+// SetTrailer sends the public metadata of err as trailers of the current RPC.
+func SetTrailer(ctx context.Context, err xerr.Error) error {
+	return grpc.SetTrailer(ctx, TrailerMetadata(err))
+}
+
+// MetadataFromTrailer extracts the error metadata sent by SetTrailer.
+func MetadataFromTrailer(md metadata.MD) map[string]string {
+	out := make(map[string]string)
+	for key, values := range md {
+		if !strings.HasPrefix(key, TrailerPrefix) || len(values) == 0 {
+			continue
+		}
+		out[strings.TrimSuffix(strings.TrimPrefix(key, TrailerPrefix), "-bin")] = values[len(values)-1]
+	}
+	return out
+}
+
+// trailerKey maps key onto the characters allowed in gRPC metadata keys.
+func trailerKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '-'
+		}
+	}, key)
+}
+
+func printableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// ServerStream counts the messages of a server stream so that an error
+// raised mid-stream reports how far the stream got.
+type ServerStream struct {
+	grpc.ServerStream
+	sent     atomic.Int64
+	received atomic.Int64
+
+	statusOpts []StatusOption
+}
+
+// WrapServerStream wraps ss. StreamServerInterceptor does this for every stream.
+func WrapServerStream(ss grpc.ServerStream) *ServerStream {
+	if ws, ok := ss.(*ServerStream); ok {
+		return ws
+	}
+	return &ServerStream{ServerStream: ss}
+}
+
+func (s *ServerStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(1)
+	}
+	return err
+}
+
+func (s *ServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received.Add(1)
+	}
+	return err
+}
+
+// Sent returns the number of messages sent so far.
+func (s *ServerStream) Sent() int64 { return s.sent.Load() }
+
+// Received returns the number of messages received so far.
+func (s *ServerStream) Received() int64 { return s.received.Load() }
+
+// Error converts err, raised while handling the current message, into a
+// status error whose ProtoReason records the stream progress. The error
+// metadata is also sent as trailers. Errors that are not xerr.Error values
+// pass through.
+func (s *ServerStream) Error(err error) error {
+	return toStatusError(s.annotate(err), s.statusOpts...)
+}
+
+func (s *ServerStream) annotate(err error) error {
+	var x xerr.Error
+	if err == nil || !errors.As(err, &x) {
+		return err
+	}
+	x = xerr.WithAttrs(x,
+		slog.Int64(KeyMessagesSent, s.Sent()),
+		slog.Int64(KeyMessagesReceived, s.Received()),
+	)
+	s.SetTrailer(TrailerMetadata(x))
+	return x
+}
+
+// ClientStream converts the errors of a client stream into xerr.Error values,
+// adding the metadata the server sent as trailers.
+type ClientStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	method string
+	cfg    *interceptorConfig
+}
+
+// WrapClientStream wraps cs. StreamClientInterceptor does this for every stream.
+func WrapClientStream(cs grpc.ClientStream) *ClientStream {
+	if wc, ok := cs.(*ClientStream); ok {
+		return wc
+	}
+	return &ClientStream{ClientStream: cs, cfg: newInterceptorConfig(nil)}
+}
+
+// RecvMsg returns io.EOF at the end of the stream and xerr.Error otherwise.
+func (c *ClientStream) RecvMsg(m any) error {
+	err := c.ClientStream.RecvMsg(m)
+	if err == nil || errors.Is(err, io.EOF) {
+		return err
+	}
+	err = FromError(err)
+	if x, ok := err.(xerr.Error); ok {
+		// Trailers are complete once RecvMsg has failed. Their keys went
+		// through trailerKey, so compare against the known keys in that form.
+		known := make(map[string]struct{})
+		for key := range x.Metadata() {
+			known[trailerKey(key)] = struct{}{}
+		}
+		fields := make(map[string]any)
+		for key, value := range MetadataFromTrailer(c.Trailer()) {
+			if _, exists := known[key]; !exists {
+				fields[key] = value
+			}
+		}
+		if len(fields) > 0 {
+			err = xerr.WithFields(x, fields)
+		}
+	}
+	c.notify(err)
+	return err
+}
+
+// SendMsg returns io.EOF when the server ended the stream; the status is then
+// available from RecvMsg.
+func (c *ClientStream) SendMsg(m any) error {
+	err := c.ClientStream.SendMsg(m)
+	if err == nil || errors.Is(err, io.EOF) {
+		return err
+	}
+	err = FromError(err)
+	c.notify(err)
+	return err
+}
+
+func (c *ClientStream) notify(err error) {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = c.Context()
+	}
+	c.cfg.notify(ctx, c.method, err)
+}
@@ -0,0 +1,71 @@
+package gerr
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestTrailerMetadataRoundTrip(t *testing.T) {
+	err := xerr.WithAttrs(xerr.New(reasonStockLow, nil),
+		slog.String("Order ID", "o-1"),
+		slog.String("owner", "Zoë"),
+		xerr.Internal("db_host", "10.0.0.1"))
+
+	md := TrailerMetadata(err)
+	if _, ok := md["xerr-owner-bin"]; !ok {
+		t.Fatalf("expected non-ASCII value as binary entry, got %v", md)
+	}
+	got := MetadataFromTrailer(md)
+	if got["order-id"] != "o-1" || got["owner"] != "Zoë" {
+		t.Fatalf("unexpected metadata %v", got)
+	}
+	if _, leaked := got["db_host"]; leaked {
+		t.Fatal("internal metadata leaked into trailers")
+	}
+}
+
+func TestStreamErrorsCarryProgress(t *testing.T) {
+	hs := &healthServer{watch: func(stream healthpb.Health_WatchServer) error {
+		for i := 0; i < 2; i++ {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}); err != nil {
+				return err
+			}
+		}
+		return xerr.WithFields(xerr.New(reasonNotServing, nil), map[string]any{"cursor": "c-2", "Order ID": "o-1"})
+	}}
+	var hooked xerr.Error
+	client := dialHealthServer(t, hs, nil, []InterceptorOption{
+		WithErrorHook(func(_ context.Context, _ string, err xerr.Error) { hooked = err }),
+	})
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("recv %d: %v", i, err)
+		}
+	}
+	_, err = stream.Recv()
+	x, ok := err.(xerr.Error)
+	if !ok || x.Reason().Code() != "NOT_SERVING" {
+		t.Fatalf("expected NOT_SERVING xerr.Error, got %T %s", err, err)
+	}
+	if md := x.Metadata(); md[KeyMessagesSent] != "2" || md["cursor"] != "c-2" || md["Order ID"] != "o-1" {
+		t.Fatalf("unexpected metadata %v", md)
+	}
+	if _, dup := x.Metadata()["order-id"]; dup {
+		t.Fatalf("trailer duplicated metadata already carried by the reason: %v", x.Metadata())
+	}
+	if MetadataFromTrailer(stream.Trailer())["cursor"] != "c-2" {
+		t.Fatalf("expected cursor trailer, got %v", stream.Trailer())
+	}
+	if hooked == nil || hooked.Reason().Code() != "NOT_SERVING" {
+		t.Fatal("expected the client hook to see the stream error")
+	}
+}
@@ -0,0 +1,171 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Catalog looks up message templates by error code and locale.
+type Catalog interface {
+	// Message returns the template for code in locale. An empty locale asks
+	// for the catalog's fallback language; other locales never fall back, so
+	// callers can try their next preference.
+	Message(code ErrorCode, locale string) (string, bool)
+}
+
+// MapCatalog is an in-memory Catalog that is safe for concurrent use. The
+// zero value has no fallback locale.
+type MapCatalog struct {
+	mu       sync.RWMutex
+	fallback string
+	messages map[string]map[ErrorCode]string
+}
+
+// NewCatalog returns an empty catalog that falls back to fallbackLocale when
+// no better match is found.
+func NewCatalog(fallbackLocale string) *MapCatalog {
+	return &MapCatalog{
+		fallback: normalizeLocale(fallbackLocale),
+		messages: make(map[string]map[ErrorCode]string),
+	}
+}
+
+// Add registers message templates for locale, replacing existing entries.
+func (c *MapCatalog) Add(locale string, messages map[ErrorCode]string) {
+	locale = normalizeLocale(locale)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.messages == nil {
+		c.messages = make(map[string]map[ErrorCode]string)
+	}
+	m, ok := c.messages[locale]
+	if !ok {
+		m = make(map[ErrorCode]string, len(messages))
+		c.messages[locale] = m
+	}
+	for code, msg := range messages {
+		m[code] = msg
+	}
+}
+
+// Message tries locale, then its base language ("pt-br" -> "pt"). An empty
+// locale uses the fallback locale.
+func (c *MapCatalog) Message(code ErrorCode, locale string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locale = normalizeLocale(locale)
+	if locale == "" {
+		locale = c.fallback
+	}
+	candidates := []string{locale}
+	if i := strings.IndexByte(locale, '-'); i > 0 {
+		candidates = append(candidates, locale[:i])
+	}
+	for _, l := range candidates {
+		if msg, ok := c.messages[l][code]; ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// LoadFS adds every file in fsys matching pattern, using the file name without
+// extension as the locale (e.g. "i18n/vi.yaml" -> "vi"). Each file maps error
+// codes to templates. decode defaults to json.Unmarshal; pass yaml.Unmarshal
+// for YAML files.
+func (c *MapCatalog) LoadFS(fsys fs.FS, pattern string, decode func([]byte, any) error) error {
+	if decode == nil {
+		decode = json.Unmarshal
+	}
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		var messages map[ErrorCode]string
+		if err := decode(data, &messages); err != nil {
+			return fmt.Errorf("failed to decode catalog '%s': %w", name, err)
+		}
+		base := path.Base(name)
+		c.Add(strings.TrimSuffix(base, path.Ext(base)), messages)
+	}
+	return nil
+}
+
+// Localize renders the message of the outermost Error in err using the best
+// locale listed in acceptLanguage, an HTTP Accept-Language header value,
+// and the catalog's fallback locale after all of them.
+// Like ToProblem it only uses the public view: templates are filled from
+// PublicMetadata, and without a catalog entry the message of Public(err) is
+// returned.
+func Localize(err error, catalog Catalog, acceptLanguage string) string {
+	if err == nil {
+		return ""
+	}
+	var x Error
+	if !errors.As(err, &x) || x.Reason() == nil {
+		return err.Error()
+	}
+	if catalog != nil {
+		code := x.Reason().Code()
+		for _, locale := range append(ParseAcceptLanguage(acceptLanguage), "") {
+			if tmpl, ok := catalog.Message(code, locale); ok {
+				return RenderMessage(tmpl, PublicMetadata(x))
+			}
+		}
+	}
+	return Public(x).Error()
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header ordered
+// by preference. The wildcard and zero-weighted entries are dropped.
+func ParseAcceptLanguage(header string) []string {
+	type entry struct {
+		locale string
+		q      float64
+	}
+	var entries []entry
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := normalizeLocale(fields[0])
+		if locale == "" || locale == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if v, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		entries = append(entries, entry{locale: locale, q: q})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].q > entries[j].q })
+
+	locales := make([]string, len(entries))
+	for i, e := range entries {
+		locales[i] = e.locale
+	}
+	return locales
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
@@ -0,0 +1,71 @@
+package xerr
+
+import (
+	"log/slog"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTemplateReason_RendersMetadata(t *testing.T) {
+	reason := NewTemplateReason("USER_NOT_FOUND", "user {user_id} not found in {region}", 404)
+	err := New(reason, nil).WithMetadata("user_id", "42")
+
+	if got := err.Error(); got != "user 42 not found in {region}" {
+		t.Fatalf("unexpected message: %q", got)
+	}
+	if ErrorToHTTPStatus(err) != 404 {
+		t.Fatalf("expected 404, got %d", ErrorToHTTPStatus(err))
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := ParseAcceptLanguage("fr-CH, fr;q=0.9, en;q=0.8, *;q=0.5, de;q=0")
+	want := []string{"fr-ch", "fr", "en"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	fsys := fstest.MapFS{
+		"i18n/en.json": {Data: []byte(`{"USER_NOT_FOUND": "User {user_id} not found"}`)},
+		"i18n/vi.json": {Data: []byte(`{"USER_NOT_FOUND": "Không tìm thấy người dùng {user_id}"}`)},
+	}
+	catalog := NewCatalog("en")
+	if err := catalog.LoadFS(fsys, "i18n/*.json", nil); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	err := New(NewHTTPReason("USER_NOT_FOUND", "user not found", 404), nil).WithMetadata("user_id", "42")
+
+	if got := Localize(err, catalog, "vi-VN,vi;q=0.9"); got != "Không tìm thấy người dùng 42" {
+		t.Fatalf("unexpected vi message: %q", got)
+	}
+	if got := Localize(err, catalog, "fr, vi;q=0.9"); got != "Không tìm thấy người dùng 42" {
+		t.Fatalf("expected the second preference, got %q", got)
+	}
+	if got := Localize(err, catalog, "ja"); got != "User 42 not found" {
+		t.Fatalf("expected fallback to en, got %q", got)
+	}
+
+	other := New(NewSimpleReason("OTHER", "other failure"), nil)
+	if got := Localize(other, catalog, "vi"); got != "other failure" {
+		t.Fatalf("expected own message, got %q", got)
+	}
+}
+
+func TestLocalize_UsesPublicMetadata(t *testing.T) {
+	catalog := NewCatalog("en")
+	catalog.Add("en", map[ErrorCode]string{"GREETING": "hi {email} from {team}"})
+	err := WithAttrs(New(NewTemplateReason("GREETING", "hello {email}", 400), nil),
+		slog.String("email", "jane@x.com"),
+		Internal("team", "billing"))
+
+	if got := Localize(err, catalog, "en"); got != "hi {email} from {team}" {
+		t.Fatalf("expected internal values to stay out, got %q", got)
+	}
+	if got := Localize(err, nil, "en"); got != "hello {email}" {
+		t.Fatalf("expected the public message without a catalog, got %q", got)
+	}
+}
@@ -0,0 +1,52 @@
+package xerr
+
+import "errors"
+
+// Reasons returns the reasons of every Error in err's chain, outermost first.
+// Non-xerr errors in between are traversed but contribute no reason.
+func Reasons(err error) []Reason {
+	var reasons []Reason
+	for ; err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(Error); ok && x.Reason() != nil {
+			reasons = append(reasons, x.Reason())
+		}
+	}
+	return reasons
+}
+
+// RootReason returns the innermost reason in err's chain, or nil if none of
+// the wrapped errors is an Error.
+func RootReason(err error) Reason {
+	var root Reason
+	for ; err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(Error); ok && x.Reason() != nil {
+			root = x.Reason()
+		}
+	}
+	return root
+}
+
+// codeTarget is an errors.Is target that matches any Error with the same code.
+type codeTarget ErrorCode
+
+func (c codeTarget) Error() string   { return string(c) }
+func (c codeTarget) Code() ErrorCode { return ErrorCode(c) }
+func (c codeTarget) Message() string { return string(c) }
+
+// HasCode reports whether any Error reachable from err carries code. Like
+// errors.Is it looks through fmt.Errorf wrappers, joined errors and Multi.
+func HasCode(err error, code ErrorCode) bool {
+	return err != nil && errors.Is(err, codeTarget(code))
+}
+
+// AsReason returns the outermost reason in err's chain whose concrete type is
+// T, e.g. AsReason[*HTTPReason](err).
+func AsReason[T Reason](err error) (T, bool) {
+	for _, r := range Reasons(err) {
+		if t, ok := r.(T); ok {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
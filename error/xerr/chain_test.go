@@ -0,0 +1,77 @@
+package xerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var (
+	reasonNotFound     = NewHTTPReason("NOT_FOUND", "user not found", 404)
+	reasonLookupFailed = NewHTTPReason("USER_LOOKUP_FAILED", "user lookup failed", 500)
+)
+
+func TestWrap_PreservesOriginal(t *testing.T) {
+	repoErr := New(reasonNotFound, nil)
+	svcErr := Wrap(fmt.Errorf("repo: %w", repoErr), reasonLookupFailed)
+
+	if repoErr.Reason().Code() != "NOT_FOUND" {
+		t.Fatalf("original error was mutated: %s", repoErr.Reason().Code())
+	}
+	if svcErr.Reason().Code() != "USER_LOOKUP_FAILED" {
+		t.Fatalf("expected outer reason, got %s", svcErr.Reason().Code())
+	}
+	if !errors.Is(svcErr, repoErr) {
+		t.Fatal("expected wrapped error to match original")
+	}
+
+	reasons := Reasons(svcErr)
+	if len(reasons) != 2 || reasons[0].Code() != "USER_LOOKUP_FAILED" || reasons[1].Code() != "NOT_FOUND" {
+		t.Fatalf("unexpected reasons: %v", reasons)
+	}
+	if root := RootReason(svcErr); root == nil || root.Code() != "NOT_FOUND" {
+		t.Fatalf("unexpected root reason: %v", root)
+	}
+}
+
+func TestRootReason_NonXerr(t *testing.T) {
+	if RootReason(errors.New("plain")) != nil {
+		t.Fatal("expected nil root reason for plain error")
+	}
+	if RootReason(nil) != nil {
+		t.Fatal("expected nil root reason for nil error")
+	}
+}
+
+func TestIs_ReasonTarget(t *testing.T) {
+	err := fmt.Errorf("handler: %w", Wrap(New(reasonNotFound, nil), reasonLookupFailed))
+
+	if !errors.Is(err, reasonNotFound) || !errors.Is(err, reasonLookupFailed) {
+		t.Fatal("expected errors.Is to match reasons at every layer")
+	}
+	if errors.Is(err, NewSimpleReason("OTHER", "other")) {
+		t.Fatal("unexpected match for unrelated reason")
+	}
+}
+
+func TestHasCode(t *testing.T) {
+	joined := errors.Join(errors.New("plain"), fmt.Errorf("wrapped: %w", New(reasonNotFound, nil)))
+	if !HasCode(joined, "NOT_FOUND") {
+		t.Fatal("expected HasCode to look through joined and wrapped errors")
+	}
+	if HasCode(joined, "USER_LOOKUP_FAILED") || HasCode(nil, "NOT_FOUND") {
+		t.Fatal("unexpected HasCode match")
+	}
+}
+
+func TestAsReason(t *testing.T) {
+	err := Wrap(New(NewTemplateReason("TPL", "tpl {x}", 400), nil), NewSimpleReason("OUTER", "outer"))
+
+	tpl, ok := AsReason[*TemplateReason](err)
+	if !ok || tpl.Code() != "TPL" {
+		t.Fatalf("expected template reason, got %v", tpl)
+	}
+	if _, ok := AsReason[*ClassifiedReason](err); ok {
+		t.Fatal("unexpected classified reason")
+	}
+}
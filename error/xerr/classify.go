@@ -0,0 +1,229 @@
+package xerr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Severity ranks how serious an error is.
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// Category tells who is responsible for an error.
+type Category string
+
+const (
+	CategoryUnknown    Category = ""
+	CategoryClient     Category = "client"
+	CategoryServer     Category = "server"
+	CategoryDependency Category = "dependency"
+	CategoryTimeout    Category = "timeout"
+)
+
+// RetryAware is implemented by reasons that know whether retrying can succeed.
+type RetryAware interface {
+	Retryable() bool
+}
+
+// SeverityAware is implemented by reasons with an explicit severity.
+type SeverityAware interface {
+	Severity() Severity
+}
+
+// CategoryAware is implemented by reasons with an explicit category.
+type CategoryAware interface {
+	Category() Category
+}
+
+// Classification bundles the optional capabilities of a ClassifiedReason.
+type Classification struct {
+	Retryable bool     `json:"retryable"`
+	Severity  Severity `json:"severity,omitempty"`
+	Category  Category `json:"category,omitempty"`
+}
+
+// ClassifiedReason is an HTTP-aware reason with explicit retryability,
+// severity and category. Zero severity or category fall back to the values
+// inferred from the HTTP status.
+type ClassifiedReason struct {
+	HTTPReason
+	Class Classification `json:"classification"`
+}
+
+func NewClassifiedReason(code ErrorCode, message string, httpStatus int, class Classification) *ClassifiedReason {
+	return &ClassifiedReason{
+		HTTPReason: HTTPReason{
+			SimpleReason: SimpleReason{
+				ErrorCode:    code,
+				ErrorMessage: message,
+			},
+			StatusCode: httpStatus,
+		},
+		Class: class,
+	}
+}
+
+func (r *ClassifiedReason) Retryable() bool {
+	return r.Class.Retryable
+}
+
+func (r *ClassifiedReason) Severity() Severity {
+	if r.Class.Severity != SeverityUnknown {
+		return r.Class.Severity
+	}
+	return severityFromStatus(r.StatusCode)
+}
+
+func (r *ClassifiedReason) Category() Category {
+	if r.Class.Category != CategoryUnknown {
+		return r.Class.Category
+	}
+	return categoryFromStatus(r.StatusCode)
+}
+
+// IsRetryable reports whether err is worth retrying. The outermost reason in
+// the chain implementing RetryAware decides; otherwise the outermost
+// HTTP-aware reason's status is used (408, 429, 502, 503 and 504 are
+// retryable). Context deadline errors are retryable, cancellation is not.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, r := range Reasons(err) {
+		if ra, ok := r.(RetryAware); ok {
+			return ra.Retryable()
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if status, ok := statusOf(err); ok {
+		return retryableStatus(status)
+	}
+	return false
+}
+
+// IsPermanent is the negation of IsRetryable for non-nil errors. Its signature
+// fits callbacks such as kafkit.WithNonRetryable.
+func IsPermanent(err error) bool {
+	return err != nil && !IsRetryable(err)
+}
+
+// SeverityOf returns the severity of the outermost reason implementing
+// SeverityAware, or one inferred from the HTTP status (5xx is an error,
+// 4xx a warning). Errors without either are treated as SeverityError.
+func SeverityOf(err error) Severity {
+	if err == nil {
+		return SeverityUnknown
+	}
+	for _, r := range Reasons(err) {
+		if sa, ok := r.(SeverityAware); ok {
+			return sa.Severity()
+		}
+	}
+	if status, ok := statusOf(err); ok {
+		return severityFromStatus(status)
+	}
+	return SeverityError
+}
+
+// CategoryOf returns the category of the outermost reason implementing
+// CategoryAware, or one inferred from context errors and the HTTP status.
+func CategoryOf(err error) Category {
+	if err == nil {
+		return CategoryUnknown
+	}
+	for _, r := range Reasons(err) {
+		if ca, ok := r.(CategoryAware); ok {
+			return ca.Category()
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CategoryTimeout
+	}
+	if status, ok := statusOf(err); ok {
+		return categoryFromStatus(status)
+	}
+	return CategoryServer
+}
+
+// reasonSeverity classifies a single reason without walking a chain.
+func reasonSeverity(r Reason) Severity {
+	if sa, ok := r.(SeverityAware); ok {
+		return sa.Severity()
+	}
+	return severityFromStatus(GetHTTPCode(r))
+}
+
+func statusOf(err error) (int, bool) {
+	for _, r := range Reasons(err) {
+		if h, ok := r.(HTTPAware); ok {
+			return h.HTTPCode(), true
+		}
+	}
+	return 0, false
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func severityFromStatus(status int) Severity {
+	switch {
+	case status >= 500:
+		return SeverityError
+	case status >= 400:
+		return SeverityWarning
+	case status > 0:
+		return SeverityInfo
+	default:
+		return SeverityUnknown
+	}
+}
+
+func categoryFromStatus(status int) Category {
+	switch {
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		return CategoryTimeout
+	case status == http.StatusBadGateway || status == http.StatusServiceUnavailable:
+		return CategoryDependency
+	case status >= 500:
+		return CategoryServer
+	case status >= 400:
+		return CategoryClient
+	default:
+		return CategoryUnknown
+	}
+}
@@ -0,0 +1,58 @@
+package xerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain", errors.New("boom"), false},
+		{"unavailable status", New(NewHTTPReason("DOWN", "down", 503), nil), true},
+		{"bad request status", New(NewHTTPReason("BAD", "bad", 400), nil), false},
+		{"deadline", fmt.Errorf("call: %w", context.DeadlineExceeded), true},
+		{"canceled", context.Canceled, false},
+		{
+			"explicit overrides status",
+			New(NewClassifiedReason("FLAKY", "flaky", 400, Classification{Retryable: true}), nil),
+			true,
+		},
+		{
+			"outer explicit wins over inner",
+			Wrap(New(NewHTTPReason("DOWN", "down", 503), nil),
+				NewClassifiedReason("GIVE_UP", "give up", 500, Classification{Retryable: false})),
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Fatalf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverityAndCategory(t *testing.T) {
+	notFound := New(NewHTTPReason("NOT_FOUND", "not found", 404), nil)
+	if SeverityOf(notFound) != SeverityWarning || CategoryOf(notFound) != CategoryClient {
+		t.Fatalf("unexpected classification: %v %v", SeverityOf(notFound), CategoryOf(notFound))
+	}
+
+	upstream := New(NewHTTPReason("UPSTREAM", "upstream", 502), nil)
+	if CategoryOf(upstream) != CategoryDependency {
+		t.Fatalf("expected dependency, got %v", CategoryOf(upstream))
+	}
+
+	critical := New(NewClassifiedReason("DB_DOWN", "db down", 500, Classification{Severity: SeverityCritical}), nil)
+	if SeverityOf(critical) != SeverityCritical || CategoryOf(critical) != CategoryServer {
+		t.Fatalf("unexpected classification: %v %v", SeverityOf(critical), CategoryOf(critical))
+	}
+}
@@ -0,0 +1,151 @@
+package xerr
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Enricher extracts attributes from ctx that NewCtx and WrapCtx attach to the
+// error's metadata, such as trace or request IDs.
+type Enricher func(ctx context.Context) []slog.Attr
+
+// Observer is notified of every error created by NewCtx and WrapCtx, e.g. to
+// record it on the active tracing span.
+type Observer func(ctx context.Context, err Error)
+
+type namedEnricher struct {
+	name string
+	fn   Enricher
+}
+
+type namedObserver struct {
+	name string
+	fn   Observer
+}
+
+var (
+	hooksMu   sync.RWMutex
+	enrichers = []namedEnricher{{name: "xerr.context", fn: contextEnricher}}
+	observers []namedObserver
+)
+
+// RegisterEnricher adds an enricher under name, replacing any enricher
+// already registered with that name. Enrichers run in registration order.
+func RegisterEnricher(name string, fn Enricher) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	next := make([]namedEnricher, 0, len(enrichers)+1)
+	replaced := false
+	for _, en := range enrichers {
+		if en.name == name {
+			en.fn, replaced = fn, true
+		}
+		next = append(next, en)
+	}
+	if !replaced {
+		next = append(next, namedEnricher{name: name, fn: fn})
+	}
+	enrichers = next
+}
+
+// RegisterObserver adds an observer under name, replacing any observer
+// already registered with that name.
+func RegisterObserver(name string, fn Observer) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	next := make([]namedObserver, 0, len(observers)+1)
+	replaced := false
+	for _, ob := range observers {
+		if ob.name == name {
+			ob.fn, replaced = fn, true
+		}
+		next = append(next, ob)
+	}
+	if !replaced {
+		next = append(next, namedObserver{name: name, fn: fn})
+	}
+	observers = next
+}
+
+// NewCtx is New with metadata pulled from ctx by the registered enrichers.
+func NewCtx(ctx context.Context, reason Reason, causeErr error, opts ...Option) Error {
+	o := newOptions(opts)
+	return withContext(ctx, &xError{
+		reason:     reason,
+		cause:      causeErr,
+		stackTrace: o.stack(),
+	})
+}
+
+// WrapCtx is Wrap with metadata pulled from ctx by the registered enrichers.
+func WrapCtx(ctx context.Context, err error, reason Reason, opts ...Option) Error {
+	o := newOptions(opts)
+	return withContext(ctx, &xError{
+		reason:     reason,
+		cause:      err,
+		stackTrace: o.stack(),
+	})
+}
+
+func withContext(ctx context.Context, e *xError) Error {
+	if ctx == nil {
+		return e
+	}
+	// Registration replaces the slices instead of mutating them, so the
+	// snapshot stays valid after the lock is released.
+	hooksMu.RLock()
+	ens, obs := enrichers, observers
+	hooksMu.RUnlock()
+
+	for _, en := range ens {
+		if en.fn == nil {
+			continue
+		}
+		if attrs := en.fn(ctx); len(attrs) > 0 {
+			e.attrs = setAttrs(e.attrs, attrs)
+		}
+	}
+	for _, ob := range obs {
+		if ob.fn != nil {
+			ob.fn(ctx, e)
+		}
+	}
+	return e
+}
+
+type ctxAttrsKey struct{}
+
+// Well-known metadata keys filled from the context by the built-in enricher.
+const (
+	KeyRequestID = "request_id"
+	KeyUserID    = "user_id"
+	KeyTenantID  = "tenant_id"
+)
+
+// ContextWithAttrs returns a context carrying attrs for the built-in
+// enricher. Attributes already in ctx with the same key are replaced.
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(ctxAttrsKey{}).([]slog.Attr)
+	return context.WithValue(ctx, ctxAttrsKey{}, setAttrs(existing, attrs))
+}
+
+// ContextWithRequestID stores the request ID for errors created with NewCtx.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return ContextWithAttrs(ctx, slog.String(KeyRequestID, id))
+}
+
+// ContextWithUserID stores the user ID for errors created with NewCtx.
+func ContextWithUserID(ctx context.Context, id string) context.Context {
+	return ContextWithAttrs(ctx, slog.String(KeyUserID, id))
+}
+
+// ContextWithTenantID stores the tenant ID for errors created with NewCtx.
+func ContextWithTenantID(ctx context.Context, id string) context.Context {
+	return ContextWithAttrs(ctx, slog.String(KeyTenantID, id))
+}
+
+func contextEnricher(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(ctxAttrsKey{}).([]slog.Attr)
+	return attrs
+}
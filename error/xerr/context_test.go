@@ -0,0 +1,44 @@
+package xerr
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func TestNewCtx_Enrichers(t *testing.T) {
+	RegisterEnricher("test.tenant", func(ctx context.Context) []slog.Attr {
+		if v, ok := ctx.Value(tenantKey{}).(string); ok {
+			return []slog.Attr{slog.String("tenant", v)}
+		}
+		return nil
+	})
+	defer RegisterEnricher("test.tenant", nil)
+
+	var observed Error
+	RegisterObserver("test.observer", func(_ context.Context, err Error) { observed = err })
+	defer RegisterObserver("test.observer", nil)
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	ctx = ContextWithUserID(ctx, "u-9")
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+
+	err := NewCtx(ctx, NewSimpleReason("X", "x"), nil)
+	md := err.Metadata()
+	if md[KeyRequestID] != "req-1" || md[KeyUserID] != "u-9" || md["tenant"] != "acme" {
+		t.Fatalf("unexpected metadata: %v", md)
+	}
+	if observed != err {
+		t.Fatal("expected observer to receive the error")
+	}
+	if frames := err.StackTrace().Frames(); len(frames) == 0 || frames[0].Function() != "github.com/nduyhai/xcore/error/xerr.TestNewCtx_Enrichers" {
+		t.Fatalf("expected stack to start at the caller, got %v", err.StackTrace().Format())
+	}
+
+	wrapped := WrapCtx(context.Background(), err, NewSimpleReason("Y", "y"))
+	if wrapped.Metadata()[KeyRequestID] != "req-1" {
+		t.Fatal("expected cause metadata to stay visible after WrapCtx")
+	}
+}
@@ -4,9 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"strings"
 )
 
+// Error is the interface of every error built by this package. It is kept
+// small so other packages can implement it; typed attributes and details are
+// reached through the Attributed and Detailed interfaces, or the Attrs, With,
+// WithAttrs, WithFields, Details and WithDetails functions, which also accept
+// implementations without them.
 type Error interface {
 	Error() string
 
@@ -27,18 +33,38 @@ type Error interface {
 	Format(s fmt.State, verb rune)
 }
 
+// Attributed is implemented by errors that carry typed attributes. Attrs
+// returns the merged view of the chain, like Metadata.
+type Attributed interface {
+	Attrs() []slog.Attr
+
+	WithAttrs(attrs ...slog.Attr) Error
+}
+
+// Detailed is implemented by errors that carry structured details. Details
+// returns the details of the whole chain.
+type Detailed interface {
+	Details() []any
+
+	WithDetails(details ...any) Error
+}
+
 type xError struct {
 	reason     Reason
 	stackTrace StackTrace
-	metadata   map[string]string
+	attrs      []slog.Attr
+	details    []any
 	cause      error
 }
 
 func (e *xError) Error() string {
-	if e.reason != nil {
-		return e.reason.Message()
+	if e.reason == nil {
+		return "unknown error"
+	}
+	if t, ok := e.reason.(Templated); ok {
+		return RenderMessage(t.MessageTemplate(), e.Metadata())
 	}
-	return "unknown error"
+	return e.reason.Message()
 }
 
 func (e *xError) Reason() Reason {
@@ -49,27 +75,61 @@ func (e *xError) StackTrace() StackTrace {
 	return e.stackTrace
 }
 
+// Metadata returns the string form of every attribute in the error chain.
+// When a key appears in several layers the outermost value wins.
 func (e *xError) Metadata() map[string]string {
-	if e.metadata == nil {
-		return make(map[string]string)
-	}
-	result := make(map[string]string)
-	for k, v := range e.metadata {
-		result[k] = v
+	attrs := e.Attrs()
+	result := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		result[a.Key] = a.Value.Resolve().String()
 	}
 	return result
 }
 
+// Attrs returns the typed attributes of every Error in the chain, outermost
+// layer first. When a key appears in several layers the outermost value wins.
+func (e *xError) Attrs() []slog.Attr {
+	return mergeAttrs(e)
+}
+
 func (e *xError) Cause() error {
 	return e.cause
 }
 
+// WithMetadata returns a copy of the error with key set to value; the receiver is not modified.
 func (e *xError) WithMetadata(key string, value string) Error {
-	if e.metadata == nil {
-		e.metadata = make(map[string]string)
-	}
-	e.metadata[key] = value
-	return e
+	return e.WithAttrs(slog.String(key, value))
+}
+
+// With returns a copy of the error with key set to a typed value.
+func (e *xError) With(key string, value any) Error {
+	return e.WithAttrs(slog.Any(key, value))
+}
+
+// WithAttrs returns a copy of the error with attrs added, replacing any
+// attribute of this layer with the same key.
+func (e *xError) WithAttrs(attrs ...slog.Attr) Error {
+	clone := *e
+	clone.attrs = setAttrs(e.attrs, attrs)
+	return &clone
+}
+
+// WithFields returns a copy of the error with every field added, in key order.
+func (e *xError) WithFields(fields map[string]any) Error {
+	return e.WithAttrs(fieldsToAttrs(fields)...)
+}
+
+// Details returns the structured details of every Error in the chain, outermost layer first.
+func (e *xError) Details() []any {
+	return mergeDetails(e)
+}
+
+// WithDetails returns a copy of the error with details appended to this layer.
+func (e *xError) WithDetails(details ...any) Error {
+	clone := *e
+	clone.details = make([]any, 0, len(e.details)+len(details))
+	clone.details = append(append(clone.details, e.details...), details...)
+	return &clone
 }
 
 func (e *xError) Unwrap() error {
@@ -83,6 +143,9 @@ func (e *xError) Is(target error) bool {
 	if e == target {
 		return true
 	}
+	if r, ok := target.(Reason); ok {
+		return e.reason != nil && e.reason.Code() == r.Code()
+	}
 	var x *xError
 	if errors.As(target, &x) {
 		// Compare reasons instead of codes
@@ -111,34 +174,23 @@ func (e *xError) Format(s fmt.State, verb rune) {
 	}
 }
 
-func New(reason Reason, causeErr error) Error {
+func New(reason Reason, causeErr error, opts ...Option) Error {
+	o := newOptions(opts)
 	return &xError{
 		reason:     reason,
 		cause:      causeErr,
-		stackTrace: NewStackTrace(),
-		metadata:   make(map[string]string),
+		stackTrace: o.stack(),
 	}
 }
 
-func Wrap(err error, reason Reason) Error {
-	if err == nil {
-		return &xError{
-			reason:     reason,
-			stackTrace: NewStackTrace(),
-			metadata:   make(map[string]string),
-		}
-	}
-
-	var se *xError
-	if errors.As(err, &se) {
-		se.reason = reason
-		return se
-	}
-
+// Wrap returns a new Error with the given reason whose cause is err. When err is
+// itself an Error it is kept untouched as the cause layer, so its reason stays
+// reachable through Reasons and RootReason.
+func Wrap(err error, reason Reason, opts ...Option) Error {
+	o := newOptions(opts)
 	return &xError{
 		reason:     reason,
 		cause:      err,
-		stackTrace: NewStackTrace(),
-		metadata:   make(map[string]string),
+		stackTrace: o.stack(),
 	}
 }
@@ -0,0 +1,105 @@
+package xerr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// FingerprintOptions controls which parts of an error feed its fingerprint.
+type FingerprintOptions struct {
+	// MaxFrames is the number of in-module frames hashed; zero uses 5.
+	// A negative value ignores the stack entirely.
+	MaxFrames int
+	// ModulePrefixes keeps only frames whose function starts with one of the
+	// prefixes, e.g. "github.com/acme/billing". Empty keeps every frame
+	// except those of the Go runtime.
+	ModulePrefixes []string
+	// IgnoreLines hashes function names only, so unrelated edits that shift
+	// line numbers do not split a group between deploys.
+	IgnoreLines bool
+}
+
+const defaultFingerprintFrames = 5
+
+// Fingerprint returns a stable, hex-encoded grouping key for err built from
+// the reason codes of the whole chain and the top frames of the outermost
+// stack. Messages and metadata are ignored, so the same failure with
+// different user IDs groups together. It returns "" for a nil error.
+func Fingerprint(err error) string {
+	return FingerprintWith(err, FingerprintOptions{IgnoreLines: true})
+}
+
+// FingerprintAttr returns Fingerprint(err) as an "error.fingerprint" log attribute.
+func FingerprintAttr(err error) slog.Attr {
+	return slog.String("error.fingerprint", Fingerprint(err))
+}
+
+// FingerprintWith is Fingerprint with explicit options.
+func FingerprintWith(err error, opts FingerprintOptions) string {
+	if err == nil {
+		return ""
+	}
+	h := sha256.New()
+	write := func(s string) {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+
+	reasons := Reasons(err)
+	for _, r := range reasons {
+		write(string(r.Code()))
+	}
+	if len(reasons) == 0 {
+		// Without any reason, fall back to the concrete error type.
+		write(fmt.Sprintf("%T", err))
+	}
+
+	if opts.MaxFrames >= 0 {
+		var x Error
+		if errors.As(err, &x) && x.StackTrace() != nil {
+			for _, f := range fingerprintFrames(x.StackTrace(), opts) {
+				write(f)
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func fingerprintFrames(st StackTrace, opts FingerprintOptions) []string {
+	limit := opts.MaxFrames
+	if limit == 0 {
+		limit = defaultFingerprintFrames
+	}
+	out := make([]string, 0, limit)
+	for _, f := range st.Frames() {
+		if len(out) == limit {
+			break
+		}
+		if !inModule(f.Function(), opts.ModulePrefixes) {
+			continue
+		}
+		if opts.IgnoreLines {
+			out = append(out, f.Function())
+		} else {
+			out = append(out, f.Function()+":"+strconv.Itoa(f.Line()))
+		}
+	}
+	return out
+}
+
+func inModule(function string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return !strings.HasPrefix(function, "runtime.")
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(function, p) {
+			return true
+		}
+	}
+	return false
+}
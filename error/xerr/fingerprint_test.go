@@ -0,0 +1,44 @@
+package xerr
+
+import (
+	"errors"
+	"testing"
+)
+
+var fpReason = NewHTTPReason("ORDER_FAILED", "order failed", 500)
+
+func failOrder(id string) error {
+	return New(fpReason, nil).WithMetadata("order_id", id)
+}
+
+func TestFingerprint_GroupsIdenticalFailures(t *testing.T) {
+	a, b := failOrder("1"), failOrder("2")
+	if Fingerprint(a) == "" || Fingerprint(a) != Fingerprint(b) {
+		t.Fatalf("expected equal fingerprints, got %s and %s", Fingerprint(a), Fingerprint(b))
+	}
+
+	other := New(NewHTTPReason("ORDER_REJECTED", "order rejected", 400), nil)
+	if Fingerprint(a) == Fingerprint(other) {
+		t.Fatal("expected different codes to produce different fingerprints")
+	}
+
+	wrapped := Wrap(a, NewSimpleReason("CHECKOUT_FAILED", "checkout failed"))
+	if Fingerprint(wrapped) == Fingerprint(Wrap(errors.New("x"), NewSimpleReason("CHECKOUT_FAILED", "checkout failed"))) {
+		t.Fatal("expected cause codes to contribute to the fingerprint")
+	}
+}
+
+func TestFingerprintWith_Stack(t *testing.T) {
+	here := New(fpReason, nil)
+	there := failOrder("1")
+
+	if FingerprintWith(here, FingerprintOptions{}) == FingerprintWith(there, FingerprintOptions{}) {
+		t.Fatal("expected different call sites to produce different fingerprints")
+	}
+	if FingerprintWith(here, FingerprintOptions{MaxFrames: -1}) != FingerprintWith(there, FingerprintOptions{MaxFrames: -1}) {
+		t.Fatal("expected equal fingerprints when the stack is ignored")
+	}
+	if Fingerprint(nil) != "" {
+		t.Fatal("expected empty fingerprint for nil")
+	}
+}
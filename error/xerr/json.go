@@ -0,0 +1,141 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+)
+
+// EncodeOptions controls how an Error is rendered by MarshalJSON and LogValue.
+type EncodeOptions struct {
+	// OmitStack drops the stack from the encoded output.
+	OmitStack bool
+	// MaxFrames keeps only the innermost N frames; zero keeps all of them.
+	MaxFrames int
+}
+
+var encodeOptions atomic.Pointer[EncodeOptions]
+
+func init() {
+	encodeOptions.Store(&EncodeOptions{})
+}
+
+// SetEncodeOptions replaces the package-wide options used by MarshalJSON and LogValue.
+func SetEncodeOptions(opts EncodeOptions) {
+	encodeOptions.Store(&opts)
+}
+
+// CurrentEncodeOptions returns the package-wide encode options.
+func CurrentEncodeOptions() EncodeOptions {
+	return *encodeOptions.Load()
+}
+
+type jsonFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+type jsonError struct {
+	Code     ErrorCode      `json:"code,omitempty"`
+	Message  string         `json:"message"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Details  []any          `json:"details,omitempty"`
+	Cause    any            `json:"cause,omitempty"`
+	Stack    []jsonFrame    `json:"stack,omitempty"`
+}
+
+// MarshalJSONWith encodes err using opts instead of the package-wide options.
+func MarshalJSONWith(err Error, opts EncodeOptions) ([]byte, error) {
+	return json.Marshal(encodeError(err, opts))
+}
+
+func (e *xError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(encodeError(e, CurrentEncodeOptions()))
+}
+
+func (e *xError) LogValue() slog.Value {
+	return logValue(e, CurrentEncodeOptions())
+}
+
+func encodeError(err error, opts EncodeOptions) any {
+	if err == nil {
+		return nil
+	}
+	x, ok := err.(Error)
+	if !ok {
+		if m, ok := err.(json.Marshaler); ok {
+			return m
+		}
+		return &jsonError{
+			Message: err.Error(),
+			Cause:   encodeError(errors.Unwrap(err), opts),
+		}
+	}
+	out := &jsonError{
+		Message: x.Error(),
+		Cause:   encodeError(x.Cause(), opts),
+	}
+	if x.Reason() != nil {
+		out.Code = x.Reason().Code()
+	}
+	out.Metadata = attrsToMap(ownAttrs(x))
+	if xe, ok := x.(*xError); ok {
+		out.Details = xe.details
+	}
+	if !opts.OmitStack {
+		out.Stack = encodeStack(x.StackTrace(), opts.MaxFrames)
+	}
+	return out
+}
+
+func encodeStack(st StackTrace, maxFrames int) []jsonFrame {
+	if st == nil {
+		return nil
+	}
+	frames := st.Frames()
+	if maxFrames > 0 && len(frames) > maxFrames {
+		frames = frames[:maxFrames]
+	}
+	out := make([]jsonFrame, 0, len(frames))
+	for _, f := range frames {
+		out = append(out, jsonFrame{Function: f.Function(), File: f.File(), Line: f.Line()})
+	}
+	return out
+}
+
+func logValue(err error, opts EncodeOptions) slog.Value {
+	x, ok := err.(Error)
+	if !ok {
+		if lv, ok := err.(slog.LogValuer); ok {
+			return lv.LogValue()
+		}
+		attrs := []slog.Attr{slog.String("message", err.Error())}
+		if cause := errors.Unwrap(err); cause != nil {
+			attrs = append(attrs, slog.Any("cause", logValue(cause, opts)))
+		}
+		return slog.GroupValue(attrs...)
+	}
+
+	attrs := make([]slog.Attr, 0, 5)
+	if x.Reason() != nil {
+		attrs = append(attrs, slog.String("code", string(x.Reason().Code())))
+	}
+	attrs = append(attrs, slog.String("message", x.Error()))
+	if md := ownAttrs(x); len(md) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "metadata", Value: slog.GroupValue(md...)})
+	}
+	if xe, ok := x.(*xError); ok && len(xe.details) > 0 {
+		attrs = append(attrs, slog.Any("details", xe.details))
+	}
+	if cause := x.Cause(); cause != nil {
+		attrs = append(attrs, slog.Any("cause", logValue(cause, opts)))
+	}
+	if !opts.OmitStack {
+		if stack := encodeStack(x.StackTrace(), opts.MaxFrames); len(stack) > 0 {
+			attrs = append(attrs, slog.Any("stack", stack))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
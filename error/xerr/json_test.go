@@ -0,0 +1,93 @@
+package xerr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMarshalJSON_CauseChainAndStack(t *testing.T) {
+	inner := New(NewSimpleReason("NOT_FOUND", "user not found"), errors.New("sql: no rows")).
+		WithMetadata("user_id", "42")
+	outer := New(NewHTTPReason("LOOKUP_FAILED", "lookup failed", 500), inner)
+
+	data, err := json.Marshal(outer)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var got struct {
+		Code  string `json:"code"`
+		Cause struct {
+			Code     string            `json:"code"`
+			Metadata map[string]string `json:"metadata"`
+			Cause    struct {
+				Message string `json:"message"`
+			} `json:"cause"`
+		} `json:"cause"`
+		Stack []struct {
+			Function string `json:"function"`
+			Line     int    `json:"line"`
+		} `json:"stack"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if got.Code != "LOOKUP_FAILED" {
+		t.Fatalf("expected code LOOKUP_FAILED, got %q", got.Code)
+	}
+	if got.Cause.Code != "NOT_FOUND" || got.Cause.Metadata["user_id"] != "42" {
+		t.Fatalf("unexpected cause: %+v", got.Cause)
+	}
+	if got.Cause.Cause.Message != "sql: no rows" {
+		t.Fatalf("expected plain cause message, got %q", got.Cause.Cause.Message)
+	}
+	if len(got.Stack) == 0 || got.Stack[0].Function == "" || got.Stack[0].Line == 0 {
+		t.Fatalf("expected structured stack, got %+v", got.Stack)
+	}
+}
+
+func TestMarshalJSONWith_StackOptions(t *testing.T) {
+	e := New(NewSimpleReason("X", "x"), nil)
+
+	data, err := MarshalJSONWith(e, EncodeOptions{OmitStack: true})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if bytes.Contains(data, []byte(`"stack"`)) {
+		t.Fatalf("expected no stack, got %s", data)
+	}
+
+	data, err = MarshalJSONWith(e, EncodeOptions{MaxFrames: 1})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var got struct {
+		Stack []json.RawMessage `json:"stack"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if len(got.Stack) != 1 {
+		t.Fatalf("expected 1 frame, got %d", len(got.Stack))
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	e := New(NewSimpleReason("PAYMENT_1", "payment failed"), nil).WithMetadata("order", "o-1")
+	logger.Error("failed", "err", e)
+
+	out := buf.String()
+	for _, want := range []string{`"code":"PAYMENT_1"`, `"order":"o-1"`, `"stack":[`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %s in log output, got %s", want, out)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package xerr
+
+import (
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// setAttrs returns a new slice holding base with attrs applied; keys already
+// present in base are replaced in place so the original order is kept.
+func setAttrs(base []slog.Attr, attrs []slog.Attr) []slog.Attr {
+	out := make([]slog.Attr, len(base), len(base)+len(attrs))
+	copy(out, base)
+	for _, a := range attrs {
+		replaced := false
+		for i := range out {
+			if out[i].Key == a.Key {
+				out[i] = a
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func fieldsToAttrs(fields map[string]any) []slog.Attr {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, fields[k]))
+	}
+	return attrs
+}
+
+// Attrs returns the typed attributes of every Error in err's chain, outermost
+// layer first. When a key appears in several layers the outermost value wins.
+func Attrs(err error) []slog.Attr {
+	return mergeAttrs(err)
+}
+
+// With returns err with key set to a typed value; see WithAttrs.
+func With(err Error, key string, value any) Error {
+	return WithAttrs(err, slog.Any(key, value))
+}
+
+// WithAttrs returns err with attrs added; err itself is not modified. Errors
+// that do not implement Attributed are wrapped in a layer with the same
+// reason holding the attributes.
+func WithAttrs(err Error, attrs ...slog.Attr) Error {
+	if err == nil {
+		return nil
+	}
+	if a, ok := err.(Attributed); ok {
+		return a.WithAttrs(attrs...)
+	}
+	return extend(err).WithAttrs(attrs...)
+}
+
+// WithFields returns err with every field added, in key order; see WithAttrs.
+func WithFields(err Error, fields map[string]any) Error {
+	return WithAttrs(err, fieldsToAttrs(fields)...)
+}
+
+// Details returns the structured details of every Error in err's chain,
+// outermost layer first.
+func Details(err error) []any {
+	return mergeDetails(err)
+}
+
+// WithDetails returns err with details appended; err itself is not modified.
+// Errors that do not implement Detailed are wrapped like in WithAttrs.
+func WithDetails(err Error, details ...any) Error {
+	if err == nil {
+		return nil
+	}
+	if d, ok := err.(Detailed); ok {
+		return d.WithDetails(details...)
+	}
+	return extend(err).WithDetails(details...)
+}
+
+// extend returns a new layer over a foreign Error that keeps its reason and
+// stack, so attributes and details can be added without losing it.
+func extend(err Error) *xError {
+	return &xError{
+		reason:     err.Reason(),
+		stackTrace: err.StackTrace(),
+		cause:      err,
+	}
+}
+
+// ownAttrs returns the attributes attached to err's own layer only. Foreign
+// implementations report their merged view.
+func ownAttrs(err Error) []slog.Attr {
+	if x, ok := err.(*xError); ok {
+		return x.attrs
+	}
+	if a, ok := err.(Attributed); ok {
+		return a.Attrs()
+	}
+	md := err.Metadata()
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.String(k, md[k]))
+	}
+	return attrs
+}
+
+// mergeAttrs collects the attributes of every Error in err's chain, outermost
+// first, keeping only the outermost occurrence of each key.
+func mergeAttrs(err error) []slog.Attr {
+	var merged []slog.Attr
+	seen := make(map[string]struct{})
+	for ; err != nil; err = errors.Unwrap(err) {
+		x, ok := err.(Error)
+		if !ok {
+			continue
+		}
+		for _, a := range ownAttrs(x) {
+			if _, dup := seen[a.Key]; dup {
+				continue
+			}
+			seen[a.Key] = struct{}{}
+			merged = append(merged, a)
+		}
+		if _, isX := x.(*xError); !isX {
+			// Foreign implementations already report their merged view.
+			break
+		}
+	}
+	return merged
+}
+
+// mergeDetails collects the details of every Error in err's chain, outermost
+// first. Foreign implementations of Detailed report their merged view.
+func mergeDetails(err error) []any {
+	var details []any
+	for ; err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(*xError); ok {
+			details = append(details, x.details...)
+			continue
+		}
+		if d, ok := err.(Detailed); ok {
+			return append(details, d.Details()...)
+		}
+	}
+	return details
+}
+
+// attrsToMap converts attributes into JSON-friendly values, keeping numbers,
+// booleans and nested groups typed.
+func attrsToMap(attrs []slog.Attr) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		out[a.Key] = valueToAny(a.Value)
+	}
+	return out
+}
+
+func valueToAny(v slog.Value) any {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindGroup:
+		return attrsToMap(v.Group())
+	default:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+		return v.Any()
+	}
+}
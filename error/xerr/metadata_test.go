@@ -0,0 +1,123 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithMetadata_CopyOnWrite(t *testing.T) {
+	base := New(NewSimpleReason("BASE", "base"), nil)
+	withID := base.WithMetadata("id", "1")
+
+	if len(base.Metadata()) != 0 {
+		t.Fatalf("receiver was mutated: %v", base.Metadata())
+	}
+	if withID.Metadata()["id"] != "1" {
+		t.Fatalf("expected id=1, got %v", withID.Metadata())
+	}
+	if withID.WithMetadata("id", "2").Metadata()["id"] != "2" || withID.Metadata()["id"] != "1" {
+		t.Fatal("expected replacement to leave the original untouched")
+	}
+}
+
+func TestWithMetadata_Concurrent(t *testing.T) {
+	sentinel := New(NewSimpleReason("SENTINEL", "sentinel"), nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e := With(sentinel, "worker", i)
+			if Attrs(e)[0].Value.Int64() != int64(i) {
+				t.Errorf("unexpected value for worker %d", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(Attrs(sentinel)) != 0 {
+		t.Fatalf("sentinel was mutated: %v", Attrs(sentinel))
+	}
+}
+
+func TestMetadata_MergedAcrossChain(t *testing.T) {
+	inner := WithFields(New(NewSimpleReason("INNER", "inner"), nil), map[string]any{
+		"user_id": 42,
+		"shared":  "inner",
+	})
+	outer := Wrap(inner, NewSimpleReason("OUTER", "outer")).WithMetadata("shared", "outer")
+
+	md := outer.Metadata()
+	if md["user_id"] != "42" || md["shared"] != "outer" {
+		t.Fatalf("unexpected merged metadata: %v", md)
+	}
+}
+
+func TestMarshalJSON_TypedMetadata(t *testing.T) {
+	e := WithFields(New(NewSimpleReason("X", "x"), nil), map[string]any{
+		"attempts": 3,
+		"retry":    true,
+		"timeout":  2 * time.Second,
+	})
+
+	data, err := MarshalJSONWith(e, EncodeOptions{OmitStack: true})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var got struct {
+		Metadata map[string]any `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if got.Metadata["attempts"] != float64(3) || got.Metadata["retry"] != true || got.Metadata["timeout"] != "2s" {
+		t.Fatalf("unexpected metadata: %v", got.Metadata)
+	}
+}
+
+// foreignError implements Error without the optional interfaces, like an
+// implementation from another package would.
+type foreignError struct {
+	reason Reason
+	md     map[string]string
+}
+
+func (f *foreignError) Error() string                 { return f.reason.Message() }
+func (f *foreignError) Reason() Reason                { return f.reason }
+func (f *foreignError) StackTrace() StackTrace        { return emptyStackTrace }
+func (f *foreignError) Metadata() map[string]string   { return f.md }
+func (f *foreignError) Cause() error                  { return nil }
+func (f *foreignError) Unwrap() error                 { return nil }
+func (f *foreignError) Is(target error) bool          { return target == f }
+func (f *foreignError) Format(s fmt.State, verb rune) {}
+func (f *foreignError) WithMetadata(key, value string) Error {
+	md := map[string]string{key: value}
+	for k, v := range f.md {
+		if k != key {
+			md[k] = v
+		}
+	}
+	return &foreignError{reason: f.reason, md: md}
+}
+
+func TestWithAttrs_ForeignError(t *testing.T) {
+	foreign := &foreignError{reason: NewSimpleReason("FOREIGN", "foreign"), md: map[string]string{"region": "eu"}}
+
+	err := WithDetails(WithAttrs(foreign, slog.Int("attempt", 2), Internal("host", "10.0.0.1")), "hint")
+	if err.Reason().Code() != "FOREIGN" || !errors.Is(err, foreign) {
+		t.Fatalf("expected the foreign error to stay reachable, got %v", Reasons(err))
+	}
+	md := PublicMetadata(err)
+	if md["attempt"] != "2" || md["region"] != "eu" || md["host"] != "" {
+		t.Fatalf("unexpected public metadata %v", md)
+	}
+	if d := Details(err); len(d) != 1 || d[0] != "hint" {
+		t.Fatalf("unexpected details %v", d)
+	}
+}
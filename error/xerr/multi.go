@@ -0,0 +1,187 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// MultiItem is a single member of a Multi, optionally keyed by a field path or index.
+type MultiItem struct {
+	Key string
+	Err error
+}
+
+// MultiPolicy selects the overall reason of a Multi from its members.
+type MultiPolicy func(items []MultiItem) Reason
+
+// PolicyFirst uses the reason of the first member that carries one.
+func PolicyFirst() MultiPolicy {
+	return func(items []MultiItem) Reason {
+		for _, item := range items {
+			if r := reasonOf(item.Err); r != nil {
+				return r
+			}
+		}
+		return nil
+	}
+}
+
+// PolicyMostSevere uses the reason with the highest Severity, breaking ties
+// by HTTP status so a 5xx member outranks 4xx ones. Remaining ties keep the
+// earliest member.
+func PolicyMostSevere() MultiPolicy {
+	return func(items []MultiItem) Reason {
+		var (
+			best         Reason
+			bestSeverity Severity
+			bestStatus   int
+		)
+		for _, item := range items {
+			r := reasonOf(item.Err)
+			if r == nil {
+				continue
+			}
+			severity, status := reasonSeverity(r), GetHTTPCode(r)
+			if best == nil || severity > bestSeverity || (severity == bestSeverity && status > bestStatus) {
+				best, bestSeverity, bestStatus = r, severity, status
+			}
+		}
+		return best
+	}
+}
+
+// PolicyExplicit always uses reason, regardless of the members.
+func PolicyExplicit(reason Reason) MultiPolicy {
+	return func([]MultiItem) Reason {
+		return reason
+	}
+}
+
+// Multi aggregates several errors while keeping each member's reason.
+// errors.Is and errors.As reach every member. The zero value is ready to use
+// with PolicyFirst. A Multi is not safe for concurrent use while it is being
+// filled.
+type Multi struct {
+	items  []MultiItem
+	policy MultiPolicy
+}
+
+// NewMulti returns an empty Multi using policy to derive its reason;
+// a nil policy defaults to PolicyFirst.
+func NewMulti(policy MultiPolicy) *Multi {
+	return &Multi{policy: policy}
+}
+
+// Append adds err without a key. Nil errors are ignored.
+func (m *Multi) Append(err error) *Multi {
+	return m.AppendKey("", err)
+}
+
+// AppendKey adds err under key, typically a field path such as "items[2].sku".
+// Nil errors are ignored.
+func (m *Multi) AppendKey(key string, err error) *Multi {
+	if err != nil {
+		m.items = append(m.items, MultiItem{Key: key, Err: err})
+	}
+	return m
+}
+
+// AppendIndex adds err keyed by its position in a batch, e.g. "[3]".
+func (m *Multi) AppendIndex(index int, err error) *Multi {
+	return m.AppendKey("["+strconv.Itoa(index)+"]", err)
+}
+
+// Len reports the number of collected errors.
+func (m *Multi) Len() int {
+	return len(m.items)
+}
+
+// Items returns a copy of the collected members.
+func (m *Multi) Items() []MultiItem {
+	items := make([]MultiItem, len(m.items))
+	copy(items, m.items)
+	return items
+}
+
+// Reason returns the overall reason chosen by the policy.
+func (m *Multi) Reason() Reason {
+	if m.policy == nil {
+		return PolicyFirst()(m.items)
+	}
+	return m.policy(m.items)
+}
+
+// HTTPCode returns the HTTP status of the overall reason.
+func (m *Multi) HTTPCode() int {
+	return GetHTTPCode(m.Reason())
+}
+
+func (m *Multi) Error() string {
+	parts := make([]string, 0, len(m.items))
+	for _, item := range m.items {
+		if item.Key != "" {
+			parts = append(parts, item.Key+": "+item.Err.Error())
+		} else {
+			parts = append(parts, item.Err.Error())
+		}
+	}
+	return fmt.Sprintf("%d error(s): %s", len(m.items), strings.Join(parts, "; "))
+}
+
+// Unwrap exposes every member to errors.Is and errors.As.
+func (m *Multi) Unwrap() []error {
+	errs := make([]error, len(m.items))
+	for i, item := range m.items {
+		errs[i] = item.Err
+	}
+	return errs
+}
+
+// Err returns nil when no error was collected, otherwise an Error carrying
+// the overall reason with the Multi as its cause.
+func (m *Multi) Err() Error {
+	if m == nil || len(m.items) == 0 {
+		return nil
+	}
+	return New(m.Reason(), m, WithCallerSkip(1))
+}
+
+type jsonMultiItem struct {
+	Key   string `json:"key,omitempty"`
+	Error any    `json:"error"`
+}
+
+// MarshalJSON encodes the members as a list.
+func (m *Multi) MarshalJSON() ([]byte, error) {
+	opts := CurrentEncodeOptions()
+	out := make([]jsonMultiItem, 0, len(m.items))
+	for _, item := range m.items {
+		out = append(out, jsonMultiItem{Key: item.Key, Error: encodeError(item.Err, opts)})
+	}
+	return json.Marshal(out)
+}
+
+func (m *Multi) LogValue() slog.Value {
+	opts := CurrentEncodeOptions()
+	attrs := make([]slog.Attr, 0, len(m.items))
+	for i, item := range m.items {
+		key := item.Key
+		if key == "" {
+			key = strconv.Itoa(i)
+		}
+		attrs = append(attrs, slog.Any(key, logValue(item.Err, opts)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func reasonOf(err error) Reason {
+	var x Error
+	if errors.As(err, &x) {
+		return x.Reason()
+	}
+	return nil
+}
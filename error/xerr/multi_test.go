@@ -0,0 +1,88 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMulti_Policies(t *testing.T) {
+	invalid := NewHTTPReason("INVALID", "invalid", 400)
+	conflict := NewHTTPReason("CONFLICT", "conflict", 409)
+	failed := NewHTTPReason("BATCH_FAILED", "batch failed", 422)
+
+	build := func(policy MultiPolicy) *Multi {
+		return NewMulti(policy).
+			AppendKey("email", New(invalid, nil)).
+			AppendIndex(1, New(conflict, nil)).
+			Append(nil)
+	}
+
+	if m := build(nil); m.Len() != 2 || m.Reason().Code() != "INVALID" {
+		t.Fatalf("expected first policy to pick INVALID, got %v", m.Reason())
+	}
+	if m := build(PolicyMostSevere()); m.HTTPCode() != 409 {
+		t.Fatalf("expected most severe status 409, got %d", m.HTTPCode())
+	}
+	if m := build(PolicyExplicit(failed)); ErrorToHTTPStatus(m.Err()) != 422 {
+		t.Fatalf("expected explicit status 422, got %d", ErrorToHTTPStatus(m.Err()))
+	}
+}
+
+func TestMulti_IsAsMembers(t *testing.T) {
+	sentinel := errors.New("boom")
+	member := New(NewSimpleReason("MEMBER", "member"), nil)
+	err := NewMulti(nil).Append(sentinel).Append(member).Err()
+
+	if !errors.Is(err, sentinel) {
+		t.Fatal("expected errors.Is to reach plain member")
+	}
+	if !errors.Is(err, member) {
+		t.Fatal("expected errors.Is to reach xerr member")
+	}
+	var m *Multi
+	if !errors.As(err, &m) || m.Len() != 2 {
+		t.Fatal("expected errors.As to find the Multi")
+	}
+}
+
+func TestMulti_EmptyErr(t *testing.T) {
+	if NewMulti(nil).Err() != nil {
+		t.Fatal("expected nil Err for empty Multi")
+	}
+}
+
+func TestMulti_ZeroValue(t *testing.T) {
+	notFound := NewHTTPReason("NOT_FOUND", "not found", 404)
+
+	var m Multi
+	m.Append(errors.New("plain")).Append(New(notFound, nil))
+	err := m.Err()
+	if err == nil || err.Reason() != notFound || m.HTTPCode() != 404 {
+		t.Fatalf("expected the first reason, got %v", err)
+	}
+}
+
+func TestMulti_MarshalJSON(t *testing.T) {
+	m := NewMulti(nil).
+		AppendKey("email", New(NewSimpleReason("INVALID", "invalid email"), nil)).
+		Append(errors.New("plain"))
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var got []struct {
+		Key   string `json:"key"`
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal failed: %v (%s)", err, data)
+	}
+	if len(got) != 2 || got[0].Key != "email" || got[0].Error.Code != "INVALID" || got[1].Error.Message != "plain" {
+		t.Fatalf("unexpected JSON: %s", data)
+	}
+}
@@ -0,0 +1,41 @@
+package xerr
+
+// Option customises a single call to New or Wrap.
+type Option func(*options)
+
+type options struct {
+	noStack bool
+	skip    int
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// WithoutStack skips stack capture for this error regardless of the StackPolicy.
+// Use it on hot paths where the error is expected and handled locally.
+func WithoutStack() Option {
+	return func(o *options) { o.noStack = true }
+}
+
+// WithCallerSkip drops n additional frames from the top of the captured stack,
+// so helpers that construct errors on behalf of their caller do not appear in it.
+func WithCallerSkip(n int) Option {
+	return func(o *options) { o.skip += n }
+}
+
+// stack captures the stack of the caller of the exported constructor that
+// called it, honouring the per-call options.
+func (o options) stack() *stackTrace {
+	if o.noStack {
+		return emptyStackTrace
+	}
+	// +2 skips stack itself and the exported constructor.
+	return captureStack(o.skip + 2)
+}
@@ -0,0 +1,124 @@
+package xerr
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// ReasonInternalPanic is the default reason assigned to recovered panics.
+var ReasonInternalPanic = NewClassifiedReason("INTERNAL_PANIC", "internal panic", http.StatusInternalServerError,
+	Classification{Severity: SeverityCritical, Category: CategoryServer})
+
+// panicFrames is the room left for the frames fromPanicSite drops: the
+// deferred function, runtime.gopanic and the runtime helpers below it.
+const panicFrames = 8
+
+var panicReason atomic.Pointer[Reason]
+
+func init() {
+	SetPanicReason(ReasonInternalPanic)
+}
+
+// SetPanicReason replaces the reason FromPanic assigns to recovered panics.
+func SetPanicReason(reason Reason) {
+	if reason != nil {
+		panicReason.Store(&reason)
+	}
+}
+
+// CurrentPanicReason returns the reason FromPanic assigns to recovered panics.
+func CurrentPanicReason() Reason {
+	return *panicReason.Load()
+}
+
+// FromPanic converts a value returned by recover into an Error. It must be
+// called from the deferred function that called recover: the goroutine is
+// still unwinding then, so the captured stack starts at the panic site rather
+// than at the recover site. The panic value is kept as the cause and under
+// the internal "panic" metadata key, so it reaches logs but never clients.
+func FromPanic(v any) Error {
+	if v == nil {
+		return nil
+	}
+	cause, ok := v.(error)
+	if !ok {
+		cause = fmt.Errorf("panic: %v", v)
+	}
+
+	return &xError{
+		reason:     CurrentPanicReason(),
+		cause:      cause,
+		stackTrace: captureStackExtra(1, panicFrames).fromPanicSite().limit(CurrentStackPolicy()),
+		attrs:      []slog.Attr{Internal("panic", fmt.Sprint(v))},
+	}
+}
+
+// Recover runs fn and converts a panic raised inside it into an Error.
+// It suits consumer workers and background jobs:
+//
+//	err := xerr.Recover(func() error { return handle(msg) })
+func Recover(fn func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = FromPanic(v)
+		}
+	}()
+	return fn()
+}
+
+// RecoverHandler wraps next so that a panic is answered with a problem+json
+// response built from FromPanic. report, when non-nil, is called with the
+// error before the response is written, e.g. to log it.
+// http.ErrAbortHandler is re-panicked so net/http can abort the connection.
+func RecoverHandler(next http.Handler, report func(r *http.Request, err Error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			err := FromPanic(v)
+			if report != nil {
+				report(r, err)
+			}
+			_ = WriteProblem(w, err, WithProblemInstance(r.URL.Path))
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limit keeps at most the number of frames policy allows.
+func (s *stackTrace) limit(policy StackPolicy) *stackTrace {
+	if policy == StackFull || len(s.pcs) <= int(policy) {
+		return s
+	}
+	return &stackTrace{pcs: s.pcs[:policy:policy], sep: s.sep}
+}
+
+// fromPanicSite drops the frames between the recovering function and the
+// code that panicked: the deferred function, runtime.gopanic and any runtime
+// helpers such as runtime.sigpanic raised by a nil dereference.
+func (s *stackTrace) fromPanicSite() *stackTrace {
+	for i, pc := range s.pcs {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil || fn.Name() != "runtime.gopanic" {
+			continue
+		}
+		j := i + 1
+		for ; j < len(s.pcs); j++ {
+			next := runtime.FuncForPC(s.pcs[j] - 1)
+			if next == nil || !strings.HasPrefix(next.Name(), "runtime.") {
+				break
+			}
+		}
+		return &stackTrace{pcs: s.pcs[j:], sep: s.sep}
+	}
+	return s
+}
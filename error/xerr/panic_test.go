@@ -0,0 +1,72 @@
+package xerr
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func panicky() error {
+	var m map[string]int
+	m["boom"] = 1
+	return nil
+}
+
+func TestRecover_CapturesPanicSite(t *testing.T) {
+	err := Recover(panicky)
+
+	var x Error
+	if !errors.As(err, &x) {
+		t.Fatalf("expected xerr.Error, got %T", err)
+	}
+	if x.Reason().Code() != "INTERNAL_PANIC" || SeverityOf(x) != SeverityCritical {
+		t.Fatalf("unexpected reason: %v", x.Reason())
+	}
+	frames := x.StackTrace().Frames()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function(), ".panicky") {
+		t.Fatalf("expected stack to start at panic site, got %v", x.StackTrace().Format())
+	}
+	if !strings.Contains(x.Metadata()["panic"], "nil map") {
+		t.Fatalf("expected panic value in metadata, got %v", x.Metadata())
+	}
+}
+
+func TestRecover_CapturesPanicSiteWithShallowPolicy(t *testing.T) {
+	defer SetStackPolicy(CurrentStackPolicy())
+	SetStackPolicy(StackDepth(2))
+
+	x := Recover(panicky).(Error)
+	frames := x.StackTrace().Frames()
+	if len(frames) != 2 || !strings.HasSuffix(frames[0].Function(), ".panicky") {
+		t.Fatalf("expected two frames from the panic site, got %v", x.StackTrace().Format())
+	}
+}
+
+func TestRecover_NoPanic(t *testing.T) {
+	sentinel := errors.New("plain")
+	if err := Recover(func() error { return sentinel }); err != sentinel {
+		t.Fatalf("expected returned error to pass through, got %v", err)
+	}
+}
+
+func TestRecoverHandler(t *testing.T) {
+	var reported Error
+	h := RecoverHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("db password=hunter2")
+	}), func(_ *http.Request, err Error) { reported = err })
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	if rec.Code != http.StatusInternalServerError || rec.Header().Get("Content-Type") != ProblemContentType {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if reported == nil || reported.Metadata()["panic"] != "db password=hunter2" {
+		t.Fatalf("expected reported panic, got %v", reported)
+	}
+	if body := rec.Body.String(); strings.Contains(body, "hunter2") || !strings.Contains(body, "INTERNAL_PANIC") {
+		t.Fatalf("expected the panic value to stay out of the response, got %s", body)
+	}
+}
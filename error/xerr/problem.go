@@ -0,0 +1,241 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 9457 problem details documents.
+const ProblemContentType = "application/problem+json"
+
+// DefaultProblemTypeBase prefixes the error code to form the problem type URI.
+const DefaultProblemTypeBase = "urn:problem-type:"
+
+// Problem is an RFC 9457 problem details document. Code is carried as an
+// extension member so clients recover the exact reason code; other extension
+// members hold the error metadata.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Code       ErrorCode
+	Extensions map[string]any
+}
+
+var problemMembers = map[string]struct{}{
+	"type": {}, "title": {}, "status": {}, "detail": {}, "instance": {}, "code": {},
+}
+
+type problemConfig struct {
+	typeBase string
+	instance string
+}
+
+type ProblemOption func(*problemConfig)
+
+// WithProblemTypeBase replaces DefaultProblemTypeBase, e.g. with
+// "https://errors.example.com/" to produce dereferenceable type URIs.
+func WithProblemTypeBase(base string) ProblemOption {
+	return func(c *problemConfig) { c.typeBase = base }
+}
+
+// WithProblemInstance sets the instance member, usually the request path.
+func WithProblemInstance(instance string) ProblemOption {
+	return func(c *problemConfig) { c.instance = instance }
+}
+
+// ToProblem renders the public view of err as a problem document, so
+// internal metadata never reaches the client. Non-xerr errors become a
+// generic 500 problem without leaking their message.
+func ToProblem(err error, opts ...ProblemOption) *Problem {
+	cfg := problemConfig{typeBase: DefaultProblemTypeBase}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var x Error
+	if !errors.As(err, &x) || x.Reason() == nil {
+		return &Problem{
+			Type:     "about:blank",
+			Title:    http.StatusText(http.StatusInternalServerError),
+			Status:   http.StatusInternalServerError,
+			Instance: cfg.instance,
+		}
+	}
+
+	reason := x.Reason()
+	status := GetHTTPCode(reason)
+	title := reason.Message()
+	if _, templated := reason.(Templated); templated || title == "" {
+		title = http.StatusText(status)
+	}
+
+	// Only the public view of the error is exposed to clients.
+	pub := Public(x)
+	p := &Problem{
+		Type:       cfg.typeBase + string(reason.Code()),
+		Title:      title,
+		Status:     status,
+		Detail:     pub.Error(),
+		Instance:   cfg.instance,
+		Code:       reason.Code(),
+		Extensions: attrsToMap(Attrs(pub)),
+	}
+	if violations := FieldViolations(x); len(violations) > 0 {
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]any)
+		}
+		p.Extensions["violations"] = violations
+	}
+	return p
+}
+
+// WriteProblem writes err as an application/problem+json response.
+func WriteProblem(w http.ResponseWriter, err error, opts ...ProblemOption) error {
+	p := ToProblem(err, opts...)
+	body, mErr := json.Marshal(p)
+	if mErr != nil {
+		return mErr
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	_, wErr := w.Write(body)
+	return wErr
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	out := make(map[string]any, len(p.Extensions)+6)
+	for k, v := range p.Extensions {
+		if _, reserved := problemMembers[k]; !reserved {
+			out[k] = v
+		}
+	}
+	setIfNotEmpty := func(key, value string) {
+		if value != "" {
+			out[key] = value
+		}
+	}
+	setIfNotEmpty("type", p.Type)
+	setIfNotEmpty("title", p.Title)
+	setIfNotEmpty("detail", p.Detail)
+	setIfNotEmpty("instance", p.Instance)
+	setIfNotEmpty("code", string(p.Code))
+	if p.Status != 0 {
+		out["status"] = p.Status
+	}
+	return json.Marshal(out)
+}
+
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = Problem{}
+	targets := map[string]any{
+		"type": &p.Type, "title": &p.Title, "status": &p.Status,
+		"detail": &p.Detail, "instance": &p.Instance, "code": &p.Code,
+	}
+	for key, value := range raw {
+		if target, ok := targets[key]; ok {
+			// RFC 9457 asks consumers to ignore members with the wrong type.
+			_ = json.Unmarshal(value, target)
+			continue
+		}
+		var v any
+		if err := json.Unmarshal(value, &v); err != nil {
+			return err
+		}
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]any)
+		}
+		p.Extensions[key] = v
+	}
+	return nil
+}
+
+// Err converts the problem back into an Error. The reason is taken from
+// DefaultRegistry when the code is registered, otherwise it is rebuilt from
+// the code, detail and status. Extension members become metadata and
+// violations become FieldViolation details.
+func (p *Problem) Err() Error {
+	code := p.Code
+	if code == "" {
+		if i := strings.LastIndexAny(p.Type, ":/"); i >= 0 && p.Type != "about:blank" {
+			code = ErrorCode(p.Type[i+1:])
+		}
+	}
+
+	reason, ok := LookupReason(code)
+	if !ok {
+		message := p.Detail
+		if message == "" {
+			message = p.Title
+		}
+		reason = NewHTTPReason(code, message, p.Status)
+	}
+
+	var attrs []slog.Attr
+	for _, attr := range fieldsToAttrs(p.Extensions) {
+		if attr.Key != "violations" {
+			attrs = append(attrs, attr)
+		}
+	}
+	e := WithAttrs(New(reason, nil, WithoutStack()), attrs...)
+	if violations := p.violations(); len(violations) > 0 {
+		e = WithDetails(e, violations...)
+	}
+	return e
+}
+
+func (p *Problem) violations() []any {
+	raw, ok := p.Extensions["violations"]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var list []FieldViolation
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil
+	}
+	out := make([]any, len(list))
+	for i, fv := range list {
+		out[i] = fv
+	}
+	return out
+}
+
+// ParseProblem reads an application/problem+json response body and returns
+// the Error it describes. It fails when the response is not a problem document.
+func ParseProblem(resp *http.Response) (Error, error) {
+	if resp == nil || resp.Body == nil {
+		return nil, errors.New("xerr: empty response")
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != ProblemContentType {
+		return nil, fmt.Errorf("xerr: unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var p Problem
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("xerr: invalid problem document: %w", err)
+	}
+	if p.Status == 0 {
+		p.Status = resp.StatusCode
+	}
+	return p.Err(), nil
+}
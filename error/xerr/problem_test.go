@@ -0,0 +1,70 @@
+package xerr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteProblem_RoundTrip(t *testing.T) {
+	reason := NewHTTPReason("USER_NOT_FOUND", "user not found", http.StatusNotFound)
+	srvErr := WithFields(New(reason, nil), map[string]any{"user_id": "42", "attempt": 2})
+
+	rec := httptest.NewRecorder()
+	if err := WriteProblem(rec, srvErr, WithProblemInstance("/users/42")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	resp := rec.Result()
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusNotFound || resp.Header.Get("Content-Type") != ProblemContentType {
+		t.Fatalf("unexpected response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc["type"] != "urn:problem-type:USER_NOT_FOUND" || doc["instance"] != "/users/42" ||
+		doc["status"] != float64(404) || doc["user_id"] != "42" {
+		t.Fatalf("unexpected document: %v", doc)
+	}
+
+	rec2 := httptest.NewRecorder()
+	_ = WriteProblem(rec2, srvErr)
+	parsed, err := ParseProblem(rec2.Result())
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if parsed.Reason().Code() != "USER_NOT_FOUND" || ErrorToHTTPStatus(parsed) != http.StatusNotFound {
+		t.Fatalf("unexpected parsed error: %v", parsed.Reason())
+	}
+	if parsed.Metadata()["user_id"] != "42" {
+		t.Fatalf("expected metadata to survive, got %v", parsed.Metadata())
+	}
+}
+
+func TestToProblem_Violations(t *testing.T) {
+	err := NewViolations().Add("email", "email", "must be a valid email").Err()
+
+	data, mErr := json.Marshal(ToProblem(err))
+	if mErr != nil {
+		t.Fatalf("marshal failed: %v", mErr)
+	}
+	var p Problem
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	got := FieldViolations(p.Err())
+	if p.Status != http.StatusBadRequest || len(got) != 1 || got[0].Field != "email" {
+		t.Fatalf("unexpected violations after round trip: %+v", got)
+	}
+}
+
+func TestToProblem_PlainError(t *testing.T) {
+	p := ToProblem(http.ErrHandlerTimeout)
+	if p.Status != http.StatusInternalServerError || p.Detail != "" {
+		t.Fatalf("expected opaque 500 problem, got %+v", p)
+	}
+}
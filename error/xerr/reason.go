@@ -20,7 +20,7 @@ type SimpleReason struct {
 	ErrorMessage string    `json:"error_message"`
 }
 
-func NewSimpleReason(errorCode ErrorCode, message string) Reason {
+func NewSimpleReason(errorCode ErrorCode, message string) *SimpleReason {
 	return &SimpleReason{ErrorCode: errorCode, ErrorMessage: message}
 }
 
@@ -32,12 +32,18 @@ func (r *SimpleReason) Message() string {
 	return r.ErrorMessage
 }
 
+// Error lets reason values be used as errors.Is targets, e.g.
+// errors.Is(err, ReasonNotFound) matches any Error with the same code.
+func (r *SimpleReason) Error() string {
+	return string(r.ErrorCode) + ": " + r.ErrorMessage
+}
+
 type HTTPReason struct {
 	SimpleReason
 	StatusCode int `json:"http_status_code"`
 }
 
-func NewHTTPReason(code ErrorCode, message string, httpStatus int) Reason {
+func NewHTTPReason(code ErrorCode, message string, httpStatus int) *HTTPReason {
 	return &HTTPReason{
 		SimpleReason: SimpleReason{
 			ErrorCode:    code,
@@ -0,0 +1,185 @@
+package xerr
+
+import (
+	"errors"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// internalValue marks an attribute value as internal. It resolves to the
+// wrapped value, so logs and JSON encoding see it unchanged, while the public
+// view drops it.
+type internalValue struct {
+	v slog.Value
+}
+
+func (i internalValue) LogValue() slog.Value {
+	return i.v
+}
+
+// Internal returns an attribute that is kept out of public renderings such as
+// problem+json and gRPC status details, regardless of the Redactor rules.
+func Internal(key string, value any) slog.Attr {
+	return slog.Any(key, internalValue{v: slog.AnyValue(value)})
+}
+
+// Redactor decides which metadata keys are internal. It is safe for concurrent
+// use, and the zero value treats every key as public.
+type Redactor struct {
+	mu       sync.RWMutex
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+func NewRedactor() *Redactor {
+	return &Redactor{keys: make(map[string]struct{})}
+}
+
+// MarkInternal treats the exact keys as internal.
+func (r *Redactor) MarkInternal(keys ...string) *Redactor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.keys == nil {
+		r.keys = make(map[string]struct{})
+	}
+	for _, k := range keys {
+		r.keys[k] = struct{}{}
+	}
+	return r
+}
+
+// MarkPattern treats every key matching pattern as internal.
+func (r *Redactor) MarkPattern(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.patterns = append(r.patterns, re)
+	return nil
+}
+
+// MustMarkPattern is like MarkPattern but panics on an invalid pattern.
+func (r *Redactor) MustMarkPattern(pattern string) *Redactor {
+	if err := r.MarkPattern(pattern); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// IsInternal reports whether key must not be exposed publicly.
+func (r *Redactor) IsInternal(key string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, ok := r.keys[key]; ok {
+		return true
+	}
+	for _, re := range r.patterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicAttrs returns the attributes of attrs that may be exposed to clients.
+// Groups and maps with string keys are filtered recursively with the same
+// rules; maps come back as groups, and groups left empty are dropped.
+func (r *Redactor) PublicAttrs(attrs []slog.Attr) []slog.Attr {
+	var out []slog.Attr
+	for _, a := range attrs {
+		if pub, ok := r.publicAttr(a); ok {
+			out = append(out, pub)
+		}
+	}
+	return out
+}
+
+func (r *Redactor) publicAttr(a slog.Attr) (slog.Attr, bool) {
+	if _, internal := a.Value.Any().(internalValue); internal {
+		return a, false
+	}
+	if r.IsInternal(a.Key) {
+		return a, false
+	}
+	var nested []slog.Attr
+	switch v := a.Value.Resolve(); v.Kind() {
+	case slog.KindGroup:
+		nested = v.Group()
+	case slog.KindAny:
+		var isMap bool
+		if nested, isMap = mapAttrs(v.Any()); !isMap {
+			return a, true
+		}
+	default:
+		return a, true
+	}
+	nested = r.PublicAttrs(nested)
+	if len(nested) == 0 {
+		return a, false
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(nested...)}, true
+}
+
+// mapAttrs converts a map with string keys into attributes sorted by key.
+func mapAttrs(v any) ([]slog.Attr, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	attrs := make([]slog.Attr, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		attrs = append(attrs, slog.Any(iter.Key().String(), iter.Value().Interface()))
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+	return attrs, true
+}
+
+// DefaultRedactor holds the rules used by Public, PublicAttrs and PublicMetadata.
+// It starts with patterns for common credentials and personal data, matched
+// against the last segment of a key, which names its value: "user_email" and
+// "api_key" are internal while "discarded" or "token_count" stay public.
+var DefaultRedactor = NewRedactor().
+	MustMarkPattern(`(?i)(^|[_. -])(password|passwd|secret|token|authorization|cookie|api[_-]?key|credential)s?$`).
+	MustMarkPattern(`(?i)(^|[_. -])(email|phone|ssn|card)s?$`)
+
+// PublicAttrs returns err's merged attributes without internal ones.
+func PublicAttrs(err error) []slog.Attr {
+	var x Error
+	if !errors.As(err, &x) {
+		return nil
+	}
+	return DefaultRedactor.PublicAttrs(Attrs(x))
+}
+
+// PublicMetadata is the string form of PublicAttrs.
+func PublicMetadata(err error) map[string]string {
+	attrs := PublicAttrs(err)
+	out := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		out[a.Key] = a.Value.Resolve().String()
+	}
+	return out
+}
+
+// Public returns the client-facing view of err: the outermost reason, the
+// public attributes and the details, without cause chain or stack. Templated
+// messages are rendered from public attributes only, so internal values never
+// reach the message either. Logs should keep using the original error.
+func Public(err error) Error {
+	var x Error
+	if !errors.As(err, &x) {
+		return nil
+	}
+	return &xError{
+		reason:     x.Reason(),
+		stackTrace: emptyStackTrace,
+		attrs:      DefaultRedactor.PublicAttrs(Attrs(x)),
+		details:    Details(x),
+	}
+}
@@ -0,0 +1,101 @@
+package xerr
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestPublic_DropsInternalMetadata(t *testing.T) {
+	reason := NewTemplateReason("USER_EXISTS", "user {email} already exists", 409)
+	err := WithAttrs(Wrap(New(NewSimpleReason("DB", "duplicate key"), nil), reason).
+		WithMetadata("email", "jane@example.com").
+		WithMetadata("user_id", "42"),
+		Internal("shard", "db-7"))
+
+	pub := Public(err)
+	md := pub.Metadata()
+	if _, ok := md["email"]; ok {
+		t.Fatalf("email leaked into public view: %v", md)
+	}
+	if _, ok := md["shard"]; ok {
+		t.Fatalf("internal attribute leaked into public view: %v", md)
+	}
+	if md["user_id"] != "42" {
+		t.Fatalf("expected public user_id, got %v", md)
+	}
+	if pub.Cause() != nil || len(pub.StackTrace().Frames()) != 0 {
+		t.Fatal("expected public view without cause or stack")
+	}
+	if strings.Contains(pub.Error(), "jane") {
+		t.Fatalf("sensitive value rendered into public message: %q", pub.Error())
+	}
+
+	// The full view keeps everything for logs.
+	if err.Metadata()["shard"] != "db-7" || !strings.Contains(err.Error(), "jane@example.com") {
+		t.Fatalf("expected full view to keep internal data, got %v", err.Metadata())
+	}
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "err", err)
+	if !strings.Contains(buf.String(), `"shard":"db-7"`) {
+		t.Fatalf("expected internal data in logs, got %s", buf.String())
+	}
+
+	data, _ := json.Marshal(ToProblem(err))
+	if bytes.Contains(data, []byte("jane")) || bytes.Contains(data, []byte("db-7")) {
+		t.Fatalf("problem document leaked internal data: %s", data)
+	}
+}
+
+func TestRedactor_Rules(t *testing.T) {
+	r := NewRedactor().MarkInternal("internal_note")
+	if err := r.MarkPattern(`^debug_`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.IsInternal("internal_note") || !r.IsInternal("debug_sql") || r.IsInternal("order_id") {
+		t.Fatal("unexpected redaction decision")
+	}
+	if err := r.MarkPattern(`(`); err == nil {
+		t.Fatal("expected invalid pattern error")
+	}
+}
+
+func TestDefaultRedactor_MatchesKeySegments(t *testing.T) {
+	for _, key := range []string{"discarded", "discard_reason", "classname", "token_count", "order_id"} {
+		if DefaultRedactor.IsInternal(key) {
+			t.Fatalf("expected %q to stay public", key)
+		}
+	}
+	for _, key := range []string{"email", "user_email", "api_key", "X-Api-Key", "Authorization", "refresh_token", "db.password"} {
+		if !DefaultRedactor.IsInternal(key) {
+			t.Fatalf("expected %q to be internal", key)
+		}
+	}
+}
+
+func TestPublic_RedactsNestedMetadata(t *testing.T) {
+	err := WithAttrs(New(NewSimpleReason("SIGNUP_FAILED", "signup failed"), nil),
+		slog.Group("user", slog.String("id", "42"), slog.String("email", "jane@x.com")),
+		slog.Any("contact", map[string]string{"email": "jane@x.com", "city": "Hanoi"}),
+		slog.Group("secrets", slog.String("api_key", "k-1")))
+
+	md := PublicMetadata(err)
+	for key, value := range md {
+		if strings.Contains(value, "jane@x.com") || strings.Contains(value, "k-1") {
+			t.Fatalf("nested sensitive value leaked under %q: %v", key, md)
+		}
+	}
+	if !strings.Contains(md["user"], "id=42") || !strings.Contains(md["contact"], "city=Hanoi") {
+		t.Fatalf("expected public nested values to stay, got %v", md)
+	}
+	if _, ok := md["secrets"]; ok {
+		t.Fatalf("expected fully redacted group to be dropped, got %v", md)
+	}
+
+	data, _ := json.Marshal(ToProblem(err))
+	if bytes.Contains(data, []byte("jane@x.com")) || !bytes.Contains(data, []byte(`"city":"Hanoi"`)) {
+		t.Fatalf("unexpected problem document: %s", data)
+	}
+}
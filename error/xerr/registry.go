@@ -0,0 +1,114 @@
+package xerr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// ErrDuplicateCode is returned when two different reasons claim the same ErrorCode.
+var ErrDuplicateCode = errors.New("xerr: duplicate error code")
+
+// Registry maps error codes to their canonical reasons. It is safe for
+// concurrent use, and the zero value is an empty registry.
+type Registry struct {
+	mu      sync.RWMutex
+	reasons map[ErrorCode]Reason
+}
+
+func NewRegistry() *Registry {
+	return &Registry{reasons: make(map[ErrorCode]Reason)}
+}
+
+// Register adds reasons to the registry. Registering the same reason value
+// twice is a no-op; registering a different reason under an existing code
+// fails with ErrDuplicateCode and leaves the registry unchanged.
+func (r *Registry) Register(reasons ...Reason) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := make(map[ErrorCode]Reason, len(reasons))
+	for _, reason := range reasons {
+		if reason == nil {
+			return errors.New("xerr: cannot register nil reason")
+		}
+		code := reason.Code()
+		if code == "" {
+			return fmt.Errorf("xerr: cannot register reason with empty code (message %q)", reason.Message())
+		}
+		for _, existing := range []Reason{r.reasons[code], pending[code]} {
+			if existing != nil && !sameReason(existing, reason) {
+				return fmt.Errorf("%w: '%s' is registered as %q and %q", ErrDuplicateCode, code, existing.Message(), reason.Message())
+			}
+		}
+		pending[code] = reason
+	}
+	if r.reasons == nil {
+		r.reasons = make(map[ErrorCode]Reason, len(pending))
+	}
+	for code, reason := range pending {
+		r.reasons[code] = reason
+	}
+	return nil
+}
+
+// sameReason reports whether a and b are the same reason value. Reasons of
+// uncomparable types, such as structs holding slices, are always distinct.
+func sameReason(a, b Reason) bool {
+	if !reflect.TypeOf(a).Comparable() || !reflect.TypeOf(b).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// MustRegister is like Register but panics on error. It is meant for package init.
+func (r *Registry) MustRegister(reasons ...Reason) {
+	if err := r.Register(reasons...); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the reason registered under code.
+func (r *Registry) Lookup(code ErrorCode) (Reason, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	reason, ok := r.reasons[code]
+	return reason, ok
+}
+
+// All returns every registered reason ordered by code.
+func (r *Registry) All() []Reason {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	all := make([]Reason, 0, len(r.reasons))
+	for _, reason := range r.reasons {
+		all = append(all, reason)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Code() < all[j].Code() })
+	return all
+}
+
+// DefaultRegistry is the process-wide registry used by the package-level helpers.
+var DefaultRegistry = NewRegistry()
+
+// Register adds reasons to DefaultRegistry.
+func Register(reasons ...Reason) error {
+	return DefaultRegistry.Register(reasons...)
+}
+
+// MustRegister adds reasons to DefaultRegistry and panics on duplicates.
+func MustRegister(reasons ...Reason) {
+	DefaultRegistry.MustRegister(reasons...)
+}
+
+// LookupReason returns the reason registered under code in DefaultRegistry.
+func LookupReason(code ErrorCode) (Reason, bool) {
+	return DefaultRegistry.Lookup(code)
+}
+
+// RegisteredReasons returns every reason in DefaultRegistry ordered by code.
+func RegisteredReasons() []Reason {
+	return DefaultRegistry.All()
+}
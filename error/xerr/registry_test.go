@@ -0,0 +1,80 @@
+package xerr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	notFound := NewHTTPReason("PAYMENT_1", "user not found", 404)
+	invalid := NewSimpleReason("PAYMENT_2", "invalid input")
+
+	if err := r.Register(notFound, invalid); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if err := r.Register(notFound); err != nil {
+		t.Fatalf("re-registering the same reason should be a no-op: %v", err)
+	}
+
+	clash := NewSimpleReason("PAYMENT_1", "something else")
+	other := NewSimpleReason("PAYMENT_3", "other")
+	if err := r.Register(other, clash); !errors.Is(err, ErrDuplicateCode) {
+		t.Fatalf("expected ErrDuplicateCode, got %v", err)
+	}
+	if _, ok := r.Lookup("PAYMENT_3"); ok {
+		t.Fatal("failed registration must not be partially applied")
+	}
+
+	got, ok := r.Lookup("PAYMENT_1")
+	if !ok || got != notFound {
+		t.Fatalf("unexpected lookup result: %v", got)
+	}
+
+	all := r.All()
+	if len(all) != 2 || all[0].Code() != "PAYMENT_1" || all[1].Code() != "PAYMENT_2" {
+		t.Fatalf("unexpected catalog: %v", all)
+	}
+}
+
+// taggedReason is a struct value reason that cannot be compared with ==.
+type taggedReason struct {
+	code ErrorCode
+	tags []string
+}
+
+func (r taggedReason) Code() ErrorCode { return r.code }
+func (r taggedReason) Message() string { return string(r.code) }
+
+func TestRegistry_UncomparableReason(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(taggedReason{code: "TAGGED", tags: []string{"a"}}); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if err := r.Register(taggedReason{code: "TAGGED", tags: []string{"b"}}); !errors.Is(err, ErrDuplicateCode) {
+		t.Fatalf("expected ErrDuplicateCode, got %v", err)
+	}
+}
+
+func TestRegistry_ZeroValue(t *testing.T) {
+	var r Registry
+	reason := NewSimpleReason("ZERO", "zero")
+	if err := r.Register(reason); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if got, ok := r.Lookup("ZERO"); !ok || got != reason {
+		t.Fatalf("expected the registered reason, got %v", got)
+	}
+}
+
+func TestRegistry_MustRegisterPanics(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(NewSimpleReason("DUP", "first"))
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on duplicate code")
+		}
+	}()
+	r.MustRegister(NewSimpleReason("DUP", "second"))
+}
@@ -1,38 +1,127 @@
 package xerr
 
-import "runtime"
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
 
 type StackTrace interface {
 	Format() []string
+	Frames() []StackFrame
 }
 
+// StackPolicy controls how many frames New and Wrap capture.
+type StackPolicy int
+
+const (
+	// StackOff disables stack capture entirely.
+	StackOff StackPolicy = 0
+	// StackFull captures every frame of the calling goroutine.
+	StackFull StackPolicy = -1
+
+	defaultStackDepth = 32
+)
+
+// StackDepth returns a policy that captures at most n frames.
+func StackDepth(n int) StackPolicy {
+	if n <= 0 {
+		return StackOff
+	}
+	return StackPolicy(n)
+}
+
+var stackPolicy atomic.Int64
+
+func init() {
+	stackPolicy.Store(defaultStackDepth)
+}
+
+// SetStackPolicy replaces the package-wide stack capture policy.
+func SetStackPolicy(p StackPolicy) {
+	stackPolicy.Store(int64(p))
+}
+
+// CurrentStackPolicy returns the package-wide stack capture policy.
+func CurrentStackPolicy() StackPolicy {
+	return StackPolicy(stackPolicy.Load())
+}
+
+// stackTrace holds raw program counters and resolves them into frames only
+// when Format or Frames is first called.
 type stackTrace struct {
-	frames []StackFrame
+	pcs    []uintptr
 	sep    string
+	once   sync.Once
+	frames []StackFrame
 }
 
+var emptyStackTrace = &stackTrace{sep: ":"}
+
+// NewStackTrace captures the stack of its caller according to the current StackPolicy.
 func NewStackTrace() StackTrace {
-	var pcs [32]uintptr
-	n := runtime.Callers(0, pcs[:])
-	st := make([]StackFrame, 0, n)
-	frames := runtime.CallersFrames(pcs[:n])
+	return captureStack(1)
+}
+
+// captureStack records the calling goroutine's program counters, starting skip
+// frames above the caller of captureStack.
+func captureStack(skip int) *stackTrace {
+	return captureStackExtra(skip+1, 0)
+}
+
+// captureStackExtra is captureStack with room for extra frames beyond the
+// policy depth, for callers that trim the top of the stack afterwards.
+func captureStackExtra(skip, extra int) *stackTrace {
+	policy := CurrentStackPolicy()
+	if policy == StackOff {
+		return emptyStackTrace
+	}
+
+	size := int(policy) + extra
+	if policy == StackFull {
+		size = 64
+	}
 	for {
-		frame, more := frames.Next()
-		st = append(st, newStackFrame(frame.Function, frame.File, frame.Line))
-		if !more {
-			break
+		buf := make([]uintptr, size)
+		// +2 skips runtime.Callers and captureStackExtra itself.
+		n := runtime.Callers(skip+2, buf)
+		if n < size || policy != StackFull {
+			return &stackTrace{pcs: buf[:n:n], sep: ":"}
 		}
-	}
-	return &stackTrace{
-		frames: st,
-		sep:    ":",
+		size *= 2
 	}
 }
 
-func (s stackTrace) Format() []string {
-	var str []string
-	for _, f := range s.frames {
-		str = append([]string{f.Format(s.sep)}, str...)
+func (s *stackTrace) resolve() []StackFrame {
+	s.once.Do(func() {
+		if len(s.pcs) == 0 {
+			return
+		}
+		s.frames = make([]StackFrame, 0, len(s.pcs))
+		frames := runtime.CallersFrames(s.pcs)
+		for {
+			frame, more := frames.Next()
+			s.frames = append(s.frames, newStackFrame(frame.Function, frame.File, frame.Line))
+			if !more {
+				break
+			}
+		}
+	})
+	return s.frames
+}
+
+func (s *stackTrace) Format() []string {
+	frames := s.resolve()
+	str := make([]string, 0, len(frames))
+	for i := len(frames) - 1; i >= 0; i-- {
+		str = append(str, frames[i].Format(s.sep))
 	}
 	return str
 }
+
+func (s *stackTrace) Frames() []StackFrame {
+	resolved := s.resolve()
+	frames := make([]StackFrame, len(resolved))
+	copy(frames, resolved)
+	return frames
+}
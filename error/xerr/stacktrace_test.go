@@ -0,0 +1,84 @@
+package xerr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNew_StackStartsAtCaller(t *testing.T) {
+	e := New(NewSimpleReason("X", "x"), nil)
+
+	frames := e.StackTrace().Frames()
+	if len(frames) == 0 {
+		t.Fatal("expected captured frames")
+	}
+	if !strings.HasSuffix(frames[0].Function(), "TestNew_StackStartsAtCaller") {
+		t.Fatalf("expected first frame to be the caller, got %s", frames[0].Function())
+	}
+	for _, f := range frames {
+		if strings.HasPrefix(f.Function(), "runtime.Callers") {
+			t.Fatalf("unexpected runtime.Callers frame in %v", e.StackTrace().Format())
+		}
+	}
+}
+
+func TestWrap_WithoutStack(t *testing.T) {
+	e := Wrap(errTest, NewSimpleReason("X", "x"), WithoutStack())
+	if n := len(e.StackTrace().Frames()); n != 0 {
+		t.Fatalf("expected no frames, got %d", n)
+	}
+}
+
+func TestStackPolicy(t *testing.T) {
+	defer SetStackPolicy(CurrentStackPolicy())
+
+	SetStackPolicy(StackOff)
+	if n := len(New(NewSimpleReason("X", "x"), nil).StackTrace().Frames()); n != 0 {
+		t.Fatalf("expected no frames with StackOff, got %d", n)
+	}
+
+	SetStackPolicy(StackDepth(2))
+	if n := len(New(NewSimpleReason("X", "x"), nil).StackTrace().Frames()); n != 2 {
+		t.Fatalf("expected 2 frames, got %d", n)
+	}
+
+	SetStackPolicy(StackFull)
+	if n := len(New(NewSimpleReason("X", "x"), nil).StackTrace().Frames()); n == 0 {
+		t.Fatal("expected frames with StackFull")
+	}
+}
+
+var errTest = errors.New("test")
+
+var benchReason = NewSimpleReason("BENCH", "bench")
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = New(benchReason, nil)
+	}
+}
+
+func BenchmarkNew_Symbolized(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = New(benchReason, nil).StackTrace().Frames()
+	}
+}
+
+func BenchmarkNew_WithoutStack(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = New(benchReason, nil, WithoutStack())
+	}
+}
+
+func BenchmarkNew_StackOff(b *testing.B) {
+	defer SetStackPolicy(CurrentStackPolicy())
+	SetStackPolicy(StackOff)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = New(benchReason, nil)
+	}
+}
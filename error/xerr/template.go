@@ -0,0 +1,61 @@
+package xerr
+
+import "strings"
+
+// Templated is implemented by reasons whose message contains {name}
+// placeholders that are filled from the error's metadata when rendered.
+type Templated interface {
+	MessageTemplate() string
+}
+
+// TemplateReason is an HTTP-aware reason whose message is a template,
+// e.g. "user {user_id} not found".
+type TemplateReason struct {
+	HTTPReason
+}
+
+func NewTemplateReason(code ErrorCode, template string, httpStatus int) *TemplateReason {
+	return &TemplateReason{
+		HTTPReason: HTTPReason{
+			SimpleReason: SimpleReason{
+				ErrorCode:    code,
+				ErrorMessage: template,
+			},
+			StatusCode: httpStatus,
+		},
+	}
+}
+
+func (r *TemplateReason) MessageTemplate() string {
+	return r.ErrorMessage
+}
+
+// RenderMessage replaces every {name} placeholder in template with params[name].
+// Placeholders without a matching parameter are left untouched.
+func RenderMessage(template string, params map[string]string) string {
+	if len(params) == 0 || !strings.Contains(template, "{") {
+		return template
+	}
+	var b strings.Builder
+	b.Grow(len(template))
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(template[:start])
+		if value, ok := params[template[start+1:end]]; ok {
+			b.WriteString(value)
+		} else {
+			b.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
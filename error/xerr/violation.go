@@ -0,0 +1,91 @@
+package xerr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ReasonValidationFailed is the reason used for errors built from field violations.
+var ReasonValidationFailed = NewHTTPReason("VALIDATION_FAILED", "validation failed", http.StatusBadRequest)
+
+// FieldViolation describes why a single input field was rejected.
+type FieldViolation struct {
+	// Field is the path of the offending field, e.g. "address.zip" or "items[2].sku".
+	Field string `json:"field"`
+	// Constraint names the rule that failed, e.g. "required" or "max".
+	Constraint string `json:"constraint"`
+	// Message is the human-readable explanation.
+	Message string `json:"message"`
+	// Params holds the constraint arguments, usable for rendering localized messages.
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// Violations collects field violations and turns them into a single Error.
+// It is not safe for concurrent use.
+type Violations struct {
+	items []FieldViolation
+}
+
+// NewViolations returns an empty violation builder.
+func NewViolations() *Violations {
+	return &Violations{}
+}
+
+// Add records a violation of constraint on field.
+func (v *Violations) Add(field, constraint, message string) *Violations {
+	return v.AddViolation(FieldViolation{Field: field, Constraint: constraint, Message: message})
+}
+
+// AddWithParams records a violation together with the constraint arguments.
+func (v *Violations) AddWithParams(field, constraint, message string, params map[string]any) *Violations {
+	return v.AddViolation(FieldViolation{Field: field, Constraint: constraint, Message: message, Params: params})
+}
+
+// AddViolation records fv as is.
+func (v *Violations) AddViolation(fv FieldViolation) *Violations {
+	v.items = append(v.items, fv)
+	return v
+}
+
+// Len reports the number of recorded violations.
+func (v *Violations) Len() int {
+	return len(v.items)
+}
+
+// List returns a copy of the recorded violations.
+func (v *Violations) List() []FieldViolation {
+	items := make([]FieldViolation, len(v.items))
+	copy(items, v.items)
+	return items
+}
+
+// Err returns nil when nothing was recorded, otherwise a VALIDATION_FAILED
+// Error carrying every violation as a detail.
+func (v *Violations) Err() Error {
+	if v == nil || len(v.items) == 0 {
+		return nil
+	}
+	details := make([]any, len(v.items))
+	for i, fv := range v.items {
+		details[i] = fv
+	}
+	return WithDetails(New(ReasonValidationFailed, nil, WithCallerSkip(1)), details...)
+}
+
+// FieldViolations returns every FieldViolation attached to err's chain.
+func FieldViolations(err error) []FieldViolation {
+	var x Error
+	if !errors.As(err, &x) {
+		return nil
+	}
+	var out []FieldViolation
+	for _, d := range Details(x) {
+		switch fv := d.(type) {
+		case FieldViolation:
+			out = append(out, fv)
+		case *FieldViolation:
+			out = append(out, *fv)
+		}
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package xerr
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestViolations_Err(t *testing.T) {
+	if NewViolations().Err() != nil {
+		t.Fatal("expected nil error without violations")
+	}
+
+	err := NewViolations().
+		Add("email", "email", "must be a valid email").
+		AddWithParams("age", "min", "must be at least 18", map[string]any{"min": 18}).
+		Err()
+
+	if err.Reason().Code() != "VALIDATION_FAILED" || ErrorToHTTPStatus(err) != 400 {
+		t.Fatalf("unexpected reason: %v", err.Reason())
+	}
+
+	wrapped := Wrap(err, NewSimpleReason("SIGNUP_FAILED", "signup failed"))
+	got := FieldViolations(wrapped)
+	if len(got) != 2 || got[0].Field != "email" || got[1].Params["min"] != 18 {
+		t.Fatalf("unexpected violations: %+v", got)
+	}
+
+	data, mErr := MarshalJSONWith(err, EncodeOptions{OmitStack: true})
+	if mErr != nil {
+		t.Fatalf("marshal failed: %v", mErr)
+	}
+	if !strings.Contains(string(data), `"constraint":"min"`) || !json.Valid(data) {
+		t.Fatalf("expected violations in JSON, got %s", data)
+	}
+}
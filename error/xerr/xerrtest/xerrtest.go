@@ -0,0 +1,173 @@
+// Package xerrtest provides assertion helpers for code returning xerr errors.
+// Every helper reports through testing.TB, marks itself as a helper and
+// prints a diff on mismatch.
+package xerrtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nduyhai/xcore/error/xerr"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden rewrite
+// golden files instead of comparing them, e.g. UPDATE_GOLDEN=1 go test ./...
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// AsError fails the test unless err is, or wraps, an xerr.Error and returns it.
+func AsError(t testing.TB, err error) xerr.Error {
+	t.Helper()
+	var x xerr.Error
+	if !errors.As(err, &x) {
+		t.Fatalf("expected xerr.Error, got %T: %v", err, err)
+	}
+	return x
+}
+
+// AssertCode checks the code of the outermost reason of err.
+func AssertCode(t testing.TB, err error, want xerr.ErrorCode) {
+	t.Helper()
+	x := AsError(t, err)
+	if x.Reason() == nil {
+		t.Fatalf("error code: want %q, got no reason", want)
+	}
+	if got := x.Reason().Code(); got != want {
+		t.Errorf("error code:\n- want %q\n+ got  %q\nchain: %s", want, got, chainCodes(err))
+	}
+}
+
+// AssertHTTPStatus checks the HTTP status derived from err's reason.
+func AssertHTTPStatus(t testing.TB, err error, want int) {
+	t.Helper()
+	x := AsError(t, err)
+	if got := xerr.ErrorToHTTPStatus(x); got != want {
+		t.Errorf("http status:\n- want %d\n+ got  %d", want, got)
+	}
+}
+
+// AssertMetadata checks that err's merged metadata contains every entry of
+// want. Extra keys on the error are ignored.
+func AssertMetadata(t testing.TB, err error, want map[string]string) {
+	t.Helper()
+	got := AsError(t, err).Metadata()
+
+	keys := make([]string, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var diff strings.Builder
+	for _, k := range keys {
+		gv, ok := got[k]
+		switch {
+		case !ok:
+			fmt.Fprintf(&diff, "- %s: %q\n+ %s: <missing>\n", k, want[k], k)
+		case gv != want[k]:
+			fmt.Fprintf(&diff, "- %s: %q\n+ %s: %q\n", k, want[k], k, gv)
+		}
+	}
+	if diff.Len() > 0 {
+		t.Errorf("metadata mismatch:\n%s", diff.String())
+	}
+}
+
+// AssertCauseIs checks that target is reachable from err's cause chain with
+// errors.Is. err itself is not considered.
+func AssertCauseIs(t testing.TB, err error, target error) {
+	t.Helper()
+	x := AsError(t, err)
+	if !errors.Is(x.Cause(), target) {
+		t.Errorf("cause mismatch:\n- want cause matching %v\n+ got  chain: %s", target, chainCodes(err))
+	}
+}
+
+// AssertGolden compares the JSON encoding of err, without stack frames, with
+// the golden file at path. Set UpdateEnv to write the file instead.
+func AssertGolden(t testing.TB, err error, path string) {
+	t.Helper()
+	x := AsError(t, err)
+	data, mErr := xerr.MarshalJSONWith(x, xerr.EncodeOptions{OmitStack: true})
+	if mErr != nil {
+		t.Fatalf("failed to encode error: %v", mErr)
+	}
+	var buf bytes.Buffer
+	if iErr := json.Indent(&buf, data, "", "  "); iErr != nil {
+		t.Fatalf("failed to indent error JSON: %v", iErr)
+	}
+	buf.WriteByte('\n')
+	got := buf.String()
+
+	if os.Getenv(UpdateEnv) != "" {
+		if mkErr := os.MkdirAll(filepath.Dir(path), 0o755); mkErr != nil {
+			t.Fatalf("failed to create golden dir: %v", mkErr)
+		}
+		if wErr := os.WriteFile(path, []byte(got), 0o644); wErr != nil {
+			t.Fatalf("failed to write golden file '%s': %v", path, wErr)
+		}
+		return
+	}
+
+	want, rErr := os.ReadFile(path)
+	if rErr != nil {
+		t.Fatalf("failed to read golden file '%s' (run with %s=1 to create it): %v", path, UpdateEnv, rErr)
+	}
+	if string(want) != got {
+		t.Errorf("golden mismatch for '%s':\n%s", path, diffLines(string(want), got))
+	}
+}
+
+func chainCodes(err error) string {
+	reasons := xerr.Reasons(err)
+	codes := make([]string, len(reasons))
+	for i, r := range reasons {
+		codes[i] = string(r.Code())
+	}
+	return "[" + strings.Join(codes, " -> ") + "]"
+}
+
+// diffLines renders a minimal line diff between want and got based on their
+// longest common subsequence.
+func diffLines(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			out.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			out.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return out.String()
+}
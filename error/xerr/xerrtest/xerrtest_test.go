@@ -0,0 +1,93 @@
+package xerrtest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/nduyhai/xcore/error/xerr"
+)
+
+// recorder captures failures instead of failing the surrounding test.
+type recorder struct {
+	testing.TB
+	failed bool
+	msg    string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failed = true
+	r.msg = fmt.Sprintf(format, args...)
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// capture runs fn on its own goroutine so Fatalf can stop it like testing.T does.
+func capture(t *testing.T, fn func(tb testing.TB)) *recorder {
+	r := &recorder{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(r)
+	}()
+	<-done
+	return r
+}
+
+var (
+	errNoRows = errors.New("sql: no rows")
+	notFound  = xerr.NewHTTPReason("USER_NOT_FOUND", "user not found", 404)
+)
+
+func sampleError() error {
+	return xerr.New(notFound, errNoRows).WithMetadata("user_id", "42")
+}
+
+func TestAssertions_Pass(t *testing.T) {
+	err := sampleError()
+	AssertCode(t, err, "USER_NOT_FOUND")
+	AssertHTTPStatus(t, err, 404)
+	AssertMetadata(t, err, map[string]string{"user_id": "42"})
+	AssertCauseIs(t, err, errNoRows)
+	AssertGolden(t, err, "testdata/user_not_found.golden.json")
+}
+
+func TestAssertions_FailWithDiff(t *testing.T) {
+	err := sampleError()
+
+	r := capture(t, func(tb testing.TB) {
+		AssertMetadata(tb, err, map[string]string{"user_id": "7", "tenant": "acme"})
+	})
+	if !r.failed || !strings.Contains(r.msg, `- user_id: "7"`) || !strings.Contains(r.msg, "+ tenant: <missing>") {
+		t.Fatalf("expected metadata diff, got %q", r.msg)
+	}
+
+	r = capture(t, func(tb testing.TB) { AssertCode(tb, err, "OTHER") })
+	if !r.failed || !strings.Contains(r.msg, `+ got  "USER_NOT_FOUND"`) {
+		t.Fatalf("expected code diff, got %q", r.msg)
+	}
+
+	golden := filepath.Join(t.TempDir(), "golden.json")
+	if wErr := os.WriteFile(golden, []byte("{\n  \"code\": \"USER_NOT_FOUND\"\n}\n"), 0o644); wErr != nil {
+		t.Fatalf("failed to write golden file: %v", wErr)
+	}
+	t.Setenv(UpdateEnv, "")
+	r = capture(t, func(tb testing.TB) { AssertGolden(tb, err, golden) })
+	if !r.failed || !strings.Contains(r.msg, `+   "message": "user not found",`) {
+		t.Fatalf("expected golden diff, got %q", r.msg)
+	}
+
+	r = capture(t, func(tb testing.TB) { AssertCode(tb, errors.New("plain"), "X") })
+	if !r.failed || !strings.Contains(r.msg, "expected xerr.Error") {
+		t.Fatalf("expected type failure, got %q", r.msg)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// defaultRegistryFile is the file listing every generated reason of a package.
+const defaultRegistryFile = "registry.go"
+
+// registryVar is the variable holding every reason in the registry file.
+const registryVar = "AllReasons"
+
+// resolveConfigs expands patterns into configuration files. A pattern is a
+// file, a directory whose *.yaml and *.yml files are used, or a glob.
+// Lockfiles are skipped and each file is returned once, in pattern order.
+func resolveConfigs(patterns []string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]struct{})
+	add := func(path string) {
+		base := filepath.Base(path)
+		if strings.HasSuffix(base, ".lock.yaml") || strings.HasSuffix(base, ".lock.yml") {
+			return
+		}
+		if _, dup := seen[filepath.Clean(path)]; dup {
+			return
+		}
+		seen[filepath.Clean(path)] = struct{}{}
+		paths = append(paths, path)
+	}
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+			entries, err := os.ReadDir(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read directory '%s': %v", pattern, err)
+			}
+			for _, entry := range entries {
+				ext := filepath.Ext(entry.Name())
+				if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+					add(filepath.Join(pattern, entry.Name()))
+				}
+			}
+			continue
+		}
+		if !strings.ContainsAny(pattern, "*?[") {
+			add(pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no configuration matches '%s'", pattern)
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && !info.IsDir() {
+				add(match)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no configuration files given")
+	}
+	return paths, nil
+}
+
+// validateDomains checks the rules spanning several configurations: domains,
+// output files and codes are unique, and variable names are unique within a
+// Go package.
+func validateDomains(configs []*Config, registryFile string) error {
+	domains := make(map[string]string)
+	outputs := make(map[string]string)
+	codes := make(map[string]string)
+	names := make(map[string]string)
+	packages := make(map[string]string)
+
+	for _, config := range configs {
+		if other, dup := domains[config.Domain]; dup {
+			return fmt.Errorf("domain '%s' is defined in both '%s' and '%s'", config.Domain, other, config.source)
+		}
+		domains[config.Domain] = config.source
+
+		output := filepath.Clean(config.OutputFilePath)
+		if other, dup := outputs[output]; dup {
+			return fmt.Errorf("domains '%s' and '%s' are both generated into '%s'", other, config.Domain, output)
+		}
+		outputs[output] = config.Domain
+
+		dir := filepath.Dir(output)
+		if pkg, seen := packages[dir]; seen && pkg != config.Package {
+			return fmt.Errorf("directory '%s' mixes packages '%s' and '%s'", dir, pkg, config.Package)
+		}
+		packages[dir] = config.Package
+
+		for _, errDef := range config.Errors {
+			where := config.Domain + "." + errDef.Name
+			if other, dup := codes[errDef.GeneratedCode]; dup {
+				return fmt.Errorf("code '%s' is used by both %s and %s", errDef.GeneratedCode, other, where)
+			}
+			codes[errDef.GeneratedCode] = where
+
+			if registryFile != "" && errDef.GeneratedName == registryVar {
+				return fmt.Errorf("variable name '%s' of %s is reserved for the registry", registryVar, where)
+			}
+			key := filepath.Join(dir, errDef.GeneratedName)
+			if other, dup := names[key]; dup {
+				return fmt.Errorf("variable '%s' is generated for both %s and %s in '%s'", errDef.GeneratedName, other, where, dir)
+			}
+			names[key] = where
+		}
+	}
+
+	if registryFile != "" {
+		for dir := range packages {
+			if domain, clash := outputs[filepath.Join(dir, registryFile)]; clash {
+				return fmt.Errorf("domain '%s' is generated into the registry file '%s'", domain, registryFile)
+			}
+		}
+	}
+	return nil
+}
+
+// registryTemplate lists the reasons of every domain generated into a package.
+var registryTemplate = `// Code generated by xgen. DO NOT EDIT.
+package {{.Package}}
+
+import "` + xerrPackage + `"
+
+// ` + registryVar + ` lists every reason generated in this package, ordered by domain.
+var ` + registryVar + ` = []xerr.Reason{
+{{- range .Configs }}
+	// {{.Domain}}
+{{- range .Errors }}
+	{{.GeneratedName}},
+{{- end }}
+{{- end }}
+}
+
+func init() {
+	xerr.MustRegister(` + registryVar + `...)
+}
+`
+
+type registryData struct {
+	Package string
+	Configs []*Config
+}
+
+// generateRegistries writes one registry file per output directory and
+// registers its reasons in xerr.DefaultRegistry at init.
+func generateRegistries(configs []*Config, registryFile string) error {
+	tmpl, err := template.New("registry").Parse(registryTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse registry template: %v", err)
+	}
+
+	byDir := make(map[string]*registryData)
+	var dirs []string
+	for _, config := range configs {
+		dir := filepath.Dir(filepath.Clean(config.OutputFilePath))
+		data, ok := byDir[dir]
+		if !ok {
+			data = &registryData{Package: config.Package}
+			byDir[dir] = data
+			dirs = append(dirs, dir)
+		}
+		data.Configs = append(data.Configs, config)
+	}
+
+	for _, dir := range dirs {
+		data := byDir[dir]
+		sort.SliceStable(data.Configs, func(i, j int) bool { return data.Configs[i].Domain < data.Configs[j].Domain })
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("failed to execute registry template: %v", err)
+		}
+		if err := writeGeneratedFile(filepath.Join(dir, registryFile), buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), filePerm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveConfigsExpandsDirectoriesAndGlobs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.yaml", "b.yml", defaultLockFile, "notes.txt"} {
+		writeFile(t, filepath.Join(dir, name), "")
+	}
+
+	fromDir, err := resolveConfigs([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromGlob, err := resolveConfigs([]string{filepath.Join(dir, "*.y*ml"), filepath.Join(dir, "a.yaml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, got := range [][]string{fromDir, fromGlob} {
+		if len(got) != 2 || filepath.Base(got[0]) != "a.yaml" || filepath.Base(got[1]) != "b.yml" {
+			t.Fatalf("unexpected configurations %v", got)
+		}
+	}
+	if _, err := resolveConfigs([]string{filepath.Join(dir, "*.json")}); err == nil {
+		t.Fatal("expected an unmatched glob to fail")
+	}
+}
+
+func TestValidateDomainsDetectsDuplicates(t *testing.T) {
+	payment := &Config{Package: "errs", OutputFilePath: "errs/payment.go", Domain: "PAYMENT",
+		Errors: []ErrorDef{{Name: "Declined", GeneratedCode: "DECLINED", GeneratedName: "PaymentDeclined"}}}
+	card := &Config{Package: "errs", OutputFilePath: "errs/card.go", Domain: "CARD",
+		Errors: []ErrorDef{{Name: "Declined", GeneratedCode: "DECLINED", GeneratedName: "CardDeclined"}}}
+	if err := validateDomains([]*Config{payment, card}, defaultRegistryFile); err == nil || !strings.Contains(err.Error(), "code 'DECLINED'") {
+		t.Fatalf("expected duplicate code error, got %v", err)
+	}
+
+	card.Errors[0].GeneratedCode = "CARD_1"
+	card.Errors[0].GeneratedName = "PaymentDeclined"
+	if err := validateDomains([]*Config{payment, card}, defaultRegistryFile); err == nil || !strings.Contains(err.Error(), "variable 'PaymentDeclined'") {
+		t.Fatalf("expected duplicate variable error, got %v", err)
+	}
+
+	card.OutputFilePath = "card/errors.go"
+	if err := validateDomains([]*Config{payment, card}, defaultRegistryFile); err != nil {
+		t.Fatalf("expected names in different packages to be allowed, got %v", err)
+	}
+}
+
+func TestRunGeneratesDomainsAndRegistry(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "errs")
+	writeFile(t, filepath.Join(dir, "payment.yaml"), "package: errs\ndomain: PAYMENT\noutput_filepath: "+filepath.Join(out, "payment.go")+
+		"\nerrors:\n  - name: Declined\n    message: Card declined\n    http_status_code: 402\n")
+	writeFile(t, filepath.Join(dir, "user.yaml"), "package: errs\ndomain: USER\noutput_filepath: "+filepath.Join(out, "user.go")+
+		"\nerrors:\n  - name: NotFound\n    message: User not found\n")
+
+	paths, err := resolveConfigs([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := run(paths, filepath.Join(dir, defaultLockFile), defaultRegistryFile); err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := os.ReadFile(filepath.Join(out, defaultRegistryFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"PaymentDeclined,", "UserNotFound,", "xerr.MustRegister(AllReasons...)"} {
+		if !strings.Contains(string(registry), want) {
+			t.Fatalf("registry misses %q:\n%s", want, registry)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(out, "user.go")); err != nil {
+		t.Fatalf("expected a file per domain: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, defaultLockFile)); err != nil {
+		t.Fatalf("expected the lockfile to be written: %v", err)
+	}
+}
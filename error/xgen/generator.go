@@ -20,6 +20,8 @@ type Config struct {
 	OutputFilePath string     `yaml:"output_filepath"`
 	Domain         string     `yaml:"domain"`
 	Errors         []ErrorDef `yaml:"errors"`
+
+	source string // Path of the YAML file the configuration was read from
 }
 
 // ErrorDef defines the structure of each error in the YAML file.
@@ -28,8 +30,9 @@ type ErrorDef struct {
 	Message        string `yaml:"message"`
 	HTTPStatusCode int    `yaml:"http_status_code,omitempty"`
 	GrpcCode       string `yaml:"grpc_code,omitempty"`
+	Code           string `yaml:"code,omitempty"`    // Optional explicit code; must not change once published
 	Comment        string `yaml:"comment,omitempty"` // Optional comment
-	GeneratedCode  string // Auto-generated code based on domain + incremental number
+	GeneratedCode  string // Explicit, locked or newly numbered code
 	GeneratedName  string // Auto-generated variable name with domain prefix
 }
 
@@ -153,47 +156,97 @@ var (
 
 func main() {
 	// Define command-line flags.
-	configPath := flag.String("config", "errors.yaml", "Path to the YAML configuration file")
+	configFlag := flag.String("config", "errors.yaml", "YAML configuration files, directories or globs, comma-separated")
+	lockPath := flag.String("lock", "", "Path to the lockfile (default: "+defaultLockFile+" next to the first configuration)")
+	registryFile := flag.String("registry", defaultRegistryFile, "Name of the registry file written next to the generated files; empty disables it")
 	flag.Parse()
 
-	// Read the YAML configuration file.
-	data, err := os.ReadFile(*configPath)
+	// Extra arguments are treated as more configurations.
+	patterns := append(strings.Split(*configFlag, ","), flag.Args()...)
+	paths, err := resolveConfigs(patterns)
 	if err != nil {
-		fmt.Printf("Failed to read YAML file '%s': %v\n", *configPath, err)
+		fmt.Println(err)
 		os.Exit(1)
 	}
+	if *lockPath == "" {
+		*lockPath = filepath.Join(filepath.Dir(paths[0]), defaultLockFile)
+	}
 
-	// Unmarshal the YAML data into Config struct.
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		fmt.Printf("Failed to parse YAML file '%s': %v\n", *configPath, err)
+	if err := run(paths, *lockPath, *registryFile); err != nil {
+		fmt.Println(err)
 		os.Exit(1)
 	}
+}
 
-	// Validate the configuration.
-	if err := validateConfig(config); err != nil {
-		fmt.Printf("Configuration validation failed: %v\n", err)
-		os.Exit(1)
+// run generates every configuration in paths, one file per domain, after
+// checking codes and variable names across all of them.
+func run(paths []string, lockPath, registryFile string) error {
+	var configs []*Config
+	for _, path := range paths {
+		config, err := loadConfig(path)
+		if err != nil {
+			return err
+		}
+		if len(config.Errors) == 0 {
+			fmt.Printf("No errors defined in '%s'. Skipping.\n", path)
+			continue
+		}
+		configs = append(configs, config)
 	}
-	if len(config.Errors) == 0 {
+	if len(configs) == 0 {
 		fmt.Println("No errors defined in the configuration. Exiting.")
-		os.Exit(0)
+		return nil
 	}
 
-	// Generate auto-generated codes based on domain and ordering.
-	generateErrorCodes(&config)
+	// Assign codes, keeping those recorded in the lockfile.
+	lock, err := loadLockfile(lockPath)
+	if err != nil {
+		return err
+	}
+	for _, config := range configs {
+		if err := assignCodes(config, lock); err != nil {
+			return fmt.Errorf("code assignment failed for domain '%s': %v", config.Domain, err)
+		}
+		generateErrorNames(config)
+	}
+	if err := validateDomains(configs, registryFile); err != nil {
+		return fmt.Errorf("configuration validation failed: %v", err)
+	}
 
-	// Ensure OutputFilePath is specified.
-	if config.OutputFilePath == "" {
-		fmt.Println("'output_filepath' is not specified in the YAML configuration.")
-		os.Exit(1)
+	// Generate the Go files.
+	for _, config := range configs {
+		if err := generateGoFile(*config); err != nil {
+			return fmt.Errorf("failed to generate Go file: %v", err)
+		}
+	}
+	if registryFile != "" {
+		if err := generateRegistries(configs, registryFile); err != nil {
+			return fmt.Errorf("failed to generate registry: %v", err)
+		}
 	}
 
-	// Generate the Go file.
-	if err := generateGoFile(config); err != nil {
-		fmt.Printf("Failed to generate Go file: %v\n", err)
-		os.Exit(1)
+	// Record the assigned codes once the files have been generated.
+	return lock.save(lockPath)
+}
+
+// loadConfig reads and validates a single YAML configuration file.
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read YAML file '%s': %v", path, err)
 	}
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML file '%s': %v", path, err)
+	}
+	if err := validateConfig(config); err != nil {
+		return nil, fmt.Errorf("configuration validation failed for '%s': %v", path, err)
+	}
+	if config.OutputFilePath == "" && len(config.Errors) > 0 {
+		return nil, fmt.Errorf("'output_filepath' is not specified in '%s'", path)
+	}
+	config.source = path
+	return &config, nil
 }
 
 // validateConfig checks for unique error names and ensures required fields are present.
@@ -232,11 +285,9 @@ func titleCase(s string) string {
 	return string(b)
 }
 
-// generateErrorCodes assigns auto-generated codes and names to all errors based on domain and ordering.
-func generateErrorCodes(config *Config) {
+// generateErrorNames assigns variable names to all errors based on domain.
+func generateErrorNames(config *Config) {
 	for i := range config.Errors {
-		// Generate code as domain + incremental number (starting from 1).
-		config.Errors[i].GeneratedCode = fmt.Sprintf("%s_%d", config.Domain, i+1)
 		// Generate variable name with domain prefix (e.g., PaymentUserIDNotFound).
 		config.Errors[i].GeneratedName = titleCase(strings.ToLower(config.Domain)) + config.Errors[i].Name
 	}
@@ -263,8 +314,13 @@ func generateGoFile(config Config) error {
 		return fmt.Errorf("failed to execute Go template: %v", err)
 	}
 
+	return writeGeneratedFile(config.OutputFilePath, buf.Bytes())
+}
+
+// writeGeneratedFile writes content to path, creating its directory if needed.
+func writeGeneratedFile(path string, content []byte) error {
 	// Ensure the output directory exists.
-	dir := filepath.Dir(config.OutputFilePath)
+	dir := filepath.Dir(path)
 	if dir != "." {
 		_, statErr := os.Stat(dir)
 		dirMissing := os.IsNotExist(statErr)
@@ -277,13 +333,13 @@ func generateGoFile(config Config) error {
 	}
 
 	// Get the absolute path of the output file.
-	absPath, err := filepath.Abs(config.OutputFilePath)
+	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return fmt.Errorf("failed to get absolute path of '%s': %v", config.OutputFilePath, err)
+		return fmt.Errorf("failed to get absolute path of '%s': %v", path, err)
 	}
 
 	// Write the generated Go code to the output file.
-	if err := os.WriteFile(absPath, buf.Bytes(), filePerm); err != nil {
+	if err := os.WriteFile(absPath, content, filePerm); err != nil {
 		return fmt.Errorf("failed to write Go file '%s': %v", absPath, err)
 	}
 	fmt.Printf("Successfully generated Go file '%s'\n", absPath)
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultLockFile is the lockfile name used next to the configuration.
+const defaultLockFile = "errors.lock.yaml"
+
+const lockHeader = `# Code generated by xgen. Commit this file; do not edit it by hand
+# except to record a deliberate rename.
+`
+
+// Lockfile records every code xgen has assigned, so that published codes
+// never change and the codes of removed errors are never handed out again.
+type Lockfile struct {
+	Domains map[string]*DomainLock `yaml:"domains"`
+}
+
+// DomainLock holds the assigned codes of one domain keyed by error name,
+// and the next number to use for a new error.
+type DomainLock struct {
+	Next  int               `yaml:"next"`
+	Codes map[string]string `yaml:"codes"`
+}
+
+// loadLockfile reads the lockfile at path; a missing file yields an empty lockfile.
+func loadLockfile(path string) (*Lockfile, error) {
+	lock := &Lockfile{Domains: make(map[string]*DomainLock)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile '%s': %v", path, err)
+	}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile '%s': %v", path, err)
+	}
+	if lock.Domains == nil {
+		lock.Domains = make(map[string]*DomainLock)
+	}
+	return lock, nil
+}
+
+// save writes the lockfile to path with keys sorted, so diffs stay small.
+func (l *Lockfile) save(path string) error {
+	var buf bytes.Buffer
+	buf.WriteString(lockHeader)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(l); err != nil {
+		return fmt.Errorf("failed to encode lockfile: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), filePerm); err != nil {
+		return fmt.Errorf("failed to write lockfile '%s': %v", path, err)
+	}
+	return nil
+}
+
+func (l *Lockfile) domain(name string) *DomainLock {
+	dl, ok := l.Domains[name]
+	if !ok {
+		dl = &DomainLock{}
+		l.Domains[name] = dl
+	}
+	if dl.Codes == nil {
+		dl.Codes = make(map[string]string)
+	}
+	if dl.Next < 1 {
+		dl.Next = 1
+	}
+	return dl
+}
+
+// reserve makes sure the counter never hands out code again.
+func (dl *DomainLock) reserve(domain, code string) {
+	suffix, ok := strings.CutPrefix(code, domain+"_")
+	if !ok {
+		return
+	}
+	if n, err := strconv.Atoi(suffix); err == nil && n >= dl.Next {
+		dl.Next = n + 1
+	}
+}
+
+// assignCodes sets GeneratedCode for every error of config, recording new
+// codes in lock. Errors already in the lockfile keep their code, new errors
+// without an explicit code get the next free number of the domain. It fails
+// when an explicit code differs from the published one or is already used
+// by another error.
+func assignCodes(config *Config, lock *Lockfile) error {
+	dl := lock.domain(config.Domain)
+	owners := make(map[string]string, len(dl.Codes))
+	for name, code := range dl.Codes {
+		owners[code] = name
+		dl.reserve(config.Domain, code)
+	}
+
+	claim := func(name, code string) error {
+		if owner, taken := owners[code]; taken && owner != name {
+			return fmt.Errorf("error '%s': code '%s' is already used by '%s'", name, code, owner)
+		}
+		owners[code] = name
+		dl.Codes[name] = code
+		dl.reserve(config.Domain, code)
+		return nil
+	}
+
+	var fresh []int
+	for i, errDef := range config.Errors {
+		locked, published := dl.Codes[errDef.Name]
+		switch {
+		case errDef.Code != "":
+			if published && locked != errDef.Code {
+				return fmt.Errorf("error '%s': code changed from '%s' to '%s'; published codes must not change", errDef.Name, locked, errDef.Code)
+			}
+			if err := claim(errDef.Name, errDef.Code); err != nil {
+				return err
+			}
+			config.Errors[i].GeneratedCode = errDef.Code
+		case published:
+			config.Errors[i].GeneratedCode = locked
+		default:
+			fresh = append(fresh, i)
+		}
+	}
+
+	// Number new errors only once every explicit code has been reserved.
+	for _, i := range fresh {
+		var code string
+		for {
+			code = fmt.Sprintf("%s_%d", config.Domain, dl.Next)
+			if _, taken := owners[code]; !taken {
+				break
+			}
+			dl.Next++
+		}
+		if err := claim(config.Errors[i].Name, code); err != nil {
+			return err
+		}
+		config.Errors[i].GeneratedCode = code
+	}
+	return nil
+}
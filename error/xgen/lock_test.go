@@ -0,0 +1,91 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func paymentConfig(defs ...ErrorDef) *Config {
+	return &Config{Domain: "PAYMENT", Errors: defs}
+}
+
+func codesOf(config *Config) []string {
+	out := make([]string, len(config.Errors))
+	for i, e := range config.Errors {
+		out[i] = e.GeneratedCode
+	}
+	return out
+}
+
+func TestAssignCodesKeepsPublishedCodes(t *testing.T) {
+	lock := &Lockfile{Domains: map[string]*DomainLock{}}
+	first := paymentConfig(ErrorDef{Name: "NotFound"}, ErrorDef{Name: "Invalid"}, ErrorDef{Name: "Internal"})
+	if err := assignCodes(first, lock); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(codesOf(first), ","); got != "PAYMENT_1,PAYMENT_2,PAYMENT_3" {
+		t.Fatalf("unexpected initial codes %s", got)
+	}
+
+	// Insert at the top and drop Invalid: nothing is renumbered or reused.
+	second := paymentConfig(ErrorDef{Name: "Expired"}, ErrorDef{Name: "NotFound"}, ErrorDef{Name: "Internal"}, ErrorDef{Name: "Declined"})
+	if err := assignCodes(second, lock); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(codesOf(second), ","); got != "PAYMENT_4,PAYMENT_1,PAYMENT_3,PAYMENT_5" {
+		t.Fatalf("unexpected codes after reordering %s", got)
+	}
+	if lock.Domains["PAYMENT"].Codes["Invalid"] != "PAYMENT_2" {
+		t.Fatal("expected the removed error to stay reserved")
+	}
+}
+
+func TestAssignCodesRejectsChangedOrReusedCodes(t *testing.T) {
+	lock := &Lockfile{Domains: map[string]*DomainLock{
+		"PAYMENT": {Next: 3, Codes: map[string]string{"NotFound": "PAYMENT_1", "Invalid": "PAYMENT_2"}},
+	}}
+
+	changed := paymentConfig(ErrorDef{Name: "NotFound", Code: "PAYMENT_NOT_FOUND"})
+	if err := assignCodes(changed, lock); err == nil || !strings.Contains(err.Error(), "code changed") {
+		t.Fatalf("expected changed code to fail, got %v", err)
+	}
+
+	reused := paymentConfig(ErrorDef{Name: "Declined", Code: "PAYMENT_2"})
+	if err := assignCodes(reused, lock); err == nil || !strings.Contains(err.Error(), "already used by 'Invalid'") {
+		t.Fatalf("expected reused code to fail, got %v", err)
+	}
+}
+
+func TestAssignCodesSkipsExplicitNumbers(t *testing.T) {
+	lock := &Lockfile{Domains: map[string]*DomainLock{}}
+	config := paymentConfig(ErrorDef{Name: "New"}, ErrorDef{Name: "Pinned", Code: "PAYMENT_1"})
+	if err := assignCodes(config, lock); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(codesOf(config), ","); got != "PAYMENT_2,PAYMENT_1" {
+		t.Fatalf("unexpected codes %s", got)
+	}
+}
+
+func TestLockfileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), defaultLockFile)
+	lock, err := loadLockfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := assignCodes(paymentConfig(ErrorDef{Name: "NotFound"}), lock); err != nil {
+		t.Fatal(err)
+	}
+	if err := lock.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadLockfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dl := loaded.Domains["PAYMENT"]; dl == nil || dl.Next != 2 || dl.Codes["NotFound"] != "PAYMENT_1" {
+		t.Fatalf("unexpected lockfile %+v", loaded.Domains["PAYMENT"])
+	}
+}
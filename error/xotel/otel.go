@@ -0,0 +1,113 @@
+// Package xotel connects xerr with OpenTelemetry tracing: errors created with
+// xerr.NewCtx carry the active trace and span IDs and are recorded as
+// exception events on the active span.
+package xotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metadata keys and registration name used by Install.
+const (
+	KeyTraceID = "trace_id"
+	KeySpanID  = "span_id"
+
+	hookName = "xerr.otel"
+)
+
+type config struct {
+	setStatus bool
+	withStack bool
+}
+
+type Option func(*config)
+
+// WithSpanStatus marks the span as failed when an error is recorded.
+func WithSpanStatus(enabled bool) Option {
+	return func(c *config) { c.setStatus = enabled }
+}
+
+// WithStackTrace includes the error's stack as exception.stacktrace.
+func WithStackTrace(enabled bool) Option {
+	return func(c *config) { c.withStack = enabled }
+}
+
+// Install registers Enricher and an Observer built from opts with xerr, so
+// every xerr.NewCtx and xerr.WrapCtx call is correlated with the active span.
+func Install(opts ...Option) {
+	xerr.RegisterEnricher(hookName, Enricher)
+	xerr.RegisterObserver(hookName, Observer(opts...))
+}
+
+// Enricher returns the trace and span IDs of the span in ctx.
+func Enricher(ctx context.Context) []slog.Attr {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []slog.Attr{
+		slog.String(KeyTraceID, sc.TraceID().String()),
+		slog.String(KeySpanID, sc.SpanID().String()),
+	}
+}
+
+// Observer returns an xerr.Observer that records errors on the active span.
+func Observer(opts ...Option) xerr.Observer {
+	cfg := newConfig(opts)
+	return func(ctx context.Context, err xerr.Error) {
+		record(ctx, err, cfg)
+	}
+}
+
+// RecordError adds err to the span in ctx as an "exception" event with the
+// exception.* semantic attributes plus the xerr reason code.
+func RecordError(ctx context.Context, err error, opts ...Option) {
+	record(ctx, err, newConfig(opts))
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{setStatus: true, withStack: true}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+func record(ctx context.Context, err error, cfg config) {
+	if err == nil {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("exception.type", fmt.Sprintf("%T", err)),
+		attribute.String("exception.message", err.Error()),
+	}
+	var x xerr.Error
+	if errors.As(err, &x) {
+		if r := x.Reason(); r != nil {
+			attrs = append(attrs, attribute.String("error.code", string(r.Code())))
+		}
+		if cfg.withStack && x.StackTrace() != nil {
+			if stack := x.StackTrace().Format(); len(stack) > 0 {
+				attrs = append(attrs, attribute.String("exception.stacktrace", strings.Join(stack, "\n")))
+			}
+		}
+	}
+	span.AddEvent("exception", trace.WithAttributes(attrs...))
+	if cfg.setStatus {
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
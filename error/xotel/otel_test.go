@@ -0,0 +1,51 @@
+package xotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nduyhai/xcore/error/xerr"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstall_EnrichesAndRecords(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	Install()
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	err := xerr.NewCtx(ctx, xerr.NewHTTPReason("ORDER_FAILED", "order failed", 500), nil)
+	span.End()
+
+	md := err.Metadata()
+	if md[KeyTraceID] != span.SpanContext().TraceID().String() || md[KeySpanID] != span.SpanContext().SpanID().String() {
+		t.Fatalf("expected trace correlation metadata, got %v", md)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Fatalf("expected error status, got %v", spans[0].Status())
+	}
+	events := spans[0].Events()
+	if len(events) != 1 || events[0].Name != "exception" {
+		t.Fatalf("expected exception event, got %v", events)
+	}
+	attrs := map[string]string{}
+	for _, kv := range events[0].Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["error.code"] != "ORDER_FAILED" || attrs["exception.message"] != "order failed" || attrs["exception.stacktrace"] == "" {
+		t.Fatalf("unexpected exception attributes: %v", attrs)
+	}
+}
+
+func TestEnricher_NoSpan(t *testing.T) {
+	if attrs := Enricher(context.Background()); attrs != nil {
+		t.Fatalf("expected no attributes without a span, got %v", attrs)
+	}
+}
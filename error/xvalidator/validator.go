@@ -0,0 +1,92 @@
+// Package xvalidator turns go-playground/validator errors into xerr field violations.
+package xvalidator
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/nduyhai/xcore/error/xerr"
+)
+
+// ReasonInvalidValidation is used when the validator itself was misused, e.g.
+// given a nil or non-struct value. It signals a programming error, not bad input.
+var ReasonInvalidValidation = xerr.NewHTTPReason("INVALID_VALIDATION", "invalid validation input", http.StatusInternalServerError)
+
+type config struct {
+	trans  ut.Translator
+	reason xerr.Reason
+}
+
+type Option func(*config)
+
+// WithTranslator renders violation messages with trans instead of the
+// validator's default English text.
+func WithTranslator(trans ut.Translator) Option {
+	return func(c *config) { c.trans = trans }
+}
+
+// WithReason replaces xerr.ReasonValidationFailed as the reason of the resulting error.
+func WithReason(reason xerr.Reason) Option {
+	return func(c *config) {
+		if reason != nil {
+			c.reason = reason
+		}
+	}
+}
+
+// FromError converts the result of validator.Struct or validator.Var into an
+// xerr.Error carrying one xerr.FieldViolation per failed field. It returns nil
+// when err is nil.
+func FromError(err error, opts ...Option) xerr.Error {
+	if err == nil {
+		return nil
+	}
+	cfg := config{reason: xerr.ReasonValidationFailed}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return xerr.Wrap(err, ReasonInvalidValidation, xerr.WithCallerSkip(1))
+	}
+
+	details := make([]any, 0, len(verrs))
+	for _, fe := range verrs {
+		details = append(details, Violation(fe, cfg.trans))
+	}
+	return xerr.WithDetails(xerr.New(cfg.reason, err, xerr.WithCallerSkip(1)), details...)
+}
+
+// Violation converts a single validator.FieldError. A nil trans keeps the
+// validator's default message.
+func Violation(fe validator.FieldError, trans ut.Translator) xerr.FieldViolation {
+	fv := xerr.FieldViolation{
+		Field:      fieldPath(fe),
+		Constraint: fe.Tag(),
+		Message:    fe.Error(),
+	}
+	if trans != nil {
+		fv.Message = fe.Translate(trans)
+	}
+	if param := fe.Param(); param != "" {
+		fv.Params = map[string]any{"param": param}
+	}
+	return fv
+}
+
+// fieldPath drops the top-level struct name from the namespace, so
+// "User.Address.Zip" becomes "Address.Zip".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		return ns[i+1:]
+	}
+	if ns != "" {
+		return ns
+	}
+	return fe.Field()
+}
@@ -0,0 +1,71 @@
+package xvalidator
+
+import (
+	"testing"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	"github.com/nduyhai/xcore/error/xerr"
+)
+
+type address struct {
+	Zip string `validate:"required"`
+}
+
+type signup struct {
+	Email   string `validate:"required,email"`
+	Age     int    `validate:"min=18"`
+	Address address
+}
+
+func TestFromError(t *testing.T) {
+	validate := validator.New()
+	err := FromError(validate.Struct(signup{Email: "nope", Age: 12}))
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if err.Reason().Code() != "VALIDATION_FAILED" || xerr.ErrorToHTTPStatus(err) != 400 {
+		t.Fatalf("unexpected reason: %v", err.Reason())
+	}
+
+	got := xerr.FieldViolations(err)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 violations, got %+v", got)
+	}
+	if got[0].Field != "Email" || got[0].Constraint != "email" {
+		t.Fatalf("unexpected first violation: %+v", got[0])
+	}
+	if got[1].Constraint != "min" || got[1].Params["param"] != "18" {
+		t.Fatalf("unexpected second violation: %+v", got[1])
+	}
+	if got[2].Field != "Address.Zip" {
+		t.Fatalf("unexpected nested field path: %q", got[2].Field)
+	}
+}
+
+func TestFromError_Translated(t *testing.T) {
+	validate := validator.New()
+	uni := ut.New(en.New())
+	trans, _ := uni.GetTranslator("en")
+	if err := entranslations.RegisterDefaultTranslations(validate, trans); err != nil {
+		t.Fatalf("register translations: %v", err)
+	}
+
+	err := FromError(validate.Struct(signup{Email: "a@b.co", Age: 20}), WithTranslator(trans))
+	got := xerr.FieldViolations(err)
+	if len(got) != 1 || got[0].Message != "Zip is a required field" {
+		t.Fatalf("unexpected translated violation: %+v", got)
+	}
+}
+
+func TestFromError_NilAndMisuse(t *testing.T) {
+	if FromError(nil) != nil {
+		t.Fatal("expected nil for nil error")
+	}
+	err := FromError(validator.New().Struct(nil))
+	if err == nil || err.Reason().Code() != "INVALID_VALIDATION" {
+		t.Fatalf("expected INVALID_VALIDATION, got %v", err)
+	}
+}